@@ -6,18 +6,40 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 
 	generate "github.com/orus-io/json-schema-generate"
 )
 
 var (
-	o                     = flag.String("o", "", "The output file for the schema.")
+	o                     = flag.String("o", "", "The output file for the schema, or a directory to write a file per input schema and a helpers.go file.")
+	noHelpers             = flag.Bool("noHelpers", false, "Omit the runtime helpers, so that several generated files can share a package.")
+	helpersOnly           = flag.Bool("helpersOnly", false, "Only output the runtime helpers, for a package generated with -noHelpers.")
+	helpers               = flag.String("helpers", "", "Comma separated list of the optional runtime helpers output with -helpersOnly: 'Nullable' (requiring Go 1.18) and 'UUID'.")
+	templateDir           = flag.String("templateDir", "", "A directory of '*.tmpl' files overriding the named templates of the generated types, e.g. 'field' or 'structExtra'.")
+	filePerType           = flag.Bool("filePerType", false, "Write a file per type when the output is a directory.")
 	p                     = flag.String("p", "main", "The package that the structs are created in.")
 	i                     = flag.String("i", "", "A single file path (used for backwards compatibility).")
 	alwaysAcceptFalseFlag = flag.Bool("alwaysAcceptFalse", false, "Any field will accept decoding 'false' and ignore it")
 	useEmptyTypes         = flag.Bool("useEmptyTypes", false, "Use types with a empty types if non-required")
+	stdJSON               = flag.Bool("stdJSON", false, "Generate JSON marshalling code using encoding/json only, instead of github.com/json-iterator/go.")
+	anyOfAllMatches       = flag.Bool("anyOfAllMatches", false, "Record all the anyOf types matching a decoded value")
 	schemaKeyRequiredFlag = flag.Bool("schemaKeyRequired", false, "Allow input files with no $schema key.")
+	numberType            = flag.String("numberType", "float64", "The Go type of the numbers: 'float64', 'json.Number' or 'decimal.Decimal'.")
+	refMap                = flag.String("refMap", "", "Comma separated list of URI prefixes mapped to local directories to load the referenced schemas from, e.g. 'https://schemas.example.com/=vendor/schemas'.")
+	allowHTTP             = flag.Bool("allowHTTP", false, "Load the referenced schemas with http(s) URIs from the network.")
+	httpTimeout           = flag.Duration("httpTimeout", generate.DefaultHTTPTimeout, "The time limit of the requests loading the referenced schemas with -allowHTTP.")
+	cacheDir              = flag.String("cacheDir", "", "A directory where the referenced schemas loaded over http(s) are cached.")
+	headerFile            = flag.String("headerFile", "", "A file whose content is written as a comment at the top of the output, e.g. a license.")
+	buildTags             = flag.String("buildTags", "", "A build constraint expression added to the output, e.g. 'linux && !appengine'.")
+	importAliases         = flag.String("importAliases", "", "Comma separated list of import paths and the names they are imported as, e.g. 'net/url=neturl'.")
+	structTags            = flag.String("structTags", "", "Comma separated list of struct tags added to the fields, with the naming strategy of their JSON names, e.g. 'yaml,bson,db=snake_case'.")
+	validateTags          = flag.Bool("validateTags", false, "Add the go-playground/validator 'validate' tags of the validation keywords to the fields.")
+	initialisms           = flag.String("initialisms", "", "Comma separated list of initialisms upper cased in the generated names, 'common' being golint's list, e.g. 'common,SKU'.")
+	stringFormats         = flag.String("stringFormats", "", "Comma separated list of string formats to keep as plain strings, e.g. 'date-time,uuid'.")
 )
 
 func main() {
@@ -30,6 +52,62 @@ func main() {
 
 	flag.Parse()
 
+	opts := generate.OutputOptions{
+		PackageName:       *p,
+		AlwaysAcceptFalse: *alwaysAcceptFalseFlag,
+		UseEmptyTypes:     *useEmptyTypes,
+		AnyOfAllMatches:   *anyOfAllMatches,
+		BuildTags:         *buildTags,
+		NoHelpers:         *noHelpers,
+		TemplateDir:       *templateDir,
+		StdJSON:           *stdJSON,
+		ValidateTags:      *validateTags,
+	}
+	if *headerFile != "" {
+		header, err := ioutil.ReadFile(*headerFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error reading header file: ", err)
+			os.Exit(1)
+		}
+		opts.Header = string(header)
+	}
+	if *helpers != "" {
+		for _, helper := range strings.Split(*helpers, ",") {
+			opts.Helpers = append(opts.Helpers, strings.TrimSpace(helper))
+		}
+	}
+	if *importAliases != "" {
+		opts.ImportAliases = make(map[string]string)
+		for _, alias := range strings.Split(*importAliases, ",") {
+			parts := strings.SplitN(alias, "=", 2)
+			if len(parts) != 2 {
+				fmt.Fprintln(os.Stderr, "Invalid import alias: ", alias)
+				flag.Usage()
+				os.Exit(1)
+			}
+			opts.ImportAliases[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+		}
+	}
+	if *structTags != "" {
+		opts.Tags = make(map[string]string)
+		for _, tag := range strings.Split(*structTags, ",") {
+			parts := strings.SplitN(tag, "=", 2)
+			if len(parts) == 2 {
+				opts.Tags[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+			} else {
+				opts.Tags[strings.TrimSpace(tag)] = ""
+			}
+		}
+	}
+
+	if *helpersOnly {
+		if err := generate.OutputHelpers(openOutput(), opts); err != nil {
+			fmt.Fprintln(os.Stderr, "Failure writing the output: ", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	inputFiles := flag.Args()
 	if *i != "" {
 		inputFiles = append(inputFiles, *i)
@@ -47,6 +125,50 @@ func main() {
 	}
 
 	g := generate.New(schemas...)
+	g.SchemaKeyRequired = *schemaKeyRequiredFlag
+	switch *numberType {
+	case "float64", "json.Number", "decimal.Decimal":
+		g.NumberType = *numberType
+	default:
+		fmt.Fprintln(os.Stderr, "Invalid number type: ", *numberType)
+		flag.Usage()
+		os.Exit(1)
+	}
+	if *allowHTTP {
+		g.Loader = generate.NewHTTPEnabledLoader(*httpTimeout)
+	}
+	if *cacheDir != "" {
+		g.Loader = &generate.CachingLoader{Dir: *cacheDir, Loader: g.Loader}
+	}
+	if *refMap != "" {
+		prefixes := make(map[string]string)
+		for _, mapping := range strings.Split(*refMap, ",") {
+			parts := strings.SplitN(mapping, "=", 2)
+			if len(parts) != 2 {
+				fmt.Fprintln(os.Stderr, "Invalid reference mapping: ", mapping)
+				flag.Usage()
+				os.Exit(1)
+			}
+			prefixes[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+		}
+		g.Loader = &generate.PrefixLoader{Prefixes: prefixes, Loader: g.Loader}
+	}
+	if *initialisms != "" {
+		g.Initialisms = make(map[string]bool)
+		for _, initialism := range strings.Split(*initialisms, ",") {
+			initialism = strings.ToUpper(strings.TrimSpace(initialism))
+			if initialism == "COMMON" {
+				for _, common := range generate.CommonInitialisms {
+					g.Initialisms[common] = true
+				}
+			} else {
+				g.Initialisms[initialism] = true
+			}
+		}
+	}
+	for _, format := range strings.Split(*stringFormats, ",") {
+		delete(g.Formats, strings.TrimSpace(format))
+	}
 
 	err = g.CreateTypes()
 	if err != nil {
@@ -54,16 +176,53 @@ func main() {
 		os.Exit(1)
 	}
 
-	var w io.Writer = os.Stdout
-
-	if *o != "" {
-		w, err = os.Create(*o)
-
+	if isDir(*o) {
+		files, err := generate.OutputFiles(g, opts, *filePerType)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "Error opening output file: ", err)
-			return
+			fmt.Fprintln(os.Stderr, "Failure writing the output: ", err)
+			os.Exit(1)
+		}
+		if err := os.MkdirAll(*o, 0755); err != nil {
+			fmt.Fprintln(os.Stderr, "Error creating output directory: ", err)
+			os.Exit(1)
+		}
+		for name, code := range files {
+			if err := ioutil.WriteFile(filepath.Join(*o, name), code, 0644); err != nil {
+				fmt.Fprintln(os.Stderr, "Error writing output file: ", err)
+				os.Exit(1)
+			}
 		}
+		return
+	}
+
+	if err := generate.OutputWithOptions(openOutput(), g, opts); err != nil {
+		fmt.Fprintln(os.Stderr, "Failure writing the output: ", err)
+		os.Exit(1)
+	}
+}
+
+// openOutput returns the output file, or stdout if there is none
+func openOutput() io.Writer {
+	if *o == "" {
+		return os.Stdout
+	}
+	w, err := os.Create(*o)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error opening output file: ", err)
+		os.Exit(1)
 	}
+	return w
+}
 
-	generate.Output(w, g, *p, *alwaysAcceptFalseFlag, *useEmptyTypes)
+// isDir returns true if the output is a directory, either existing or
+// ending with a path separator
+func isDir(output string) bool {
+	if output == "" {
+		return false
+	}
+	if strings.HasSuffix(output, "/") || strings.HasSuffix(output, string(filepath.Separator)) {
+		return true
+	}
+	info, err := os.Stat(output)
+	return err == nil && info.IsDir()
 }
@@ -2,14 +2,41 @@ package generate
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"go/token"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"unicode"
 
 	"github.com/shopspring/decimal"
 )
 
+// DefaultFormats maps the string formats to the golang types of the properties
+// having them.
+var DefaultFormats = map[string]string{
+	"date-time":     "time.Time",
+	"byte":          "[]byte",
+	"uri":           "*url.URL",
+	"uri-reference": "*url.URL",
+	"ipv4":          "net.IP",
+	"ipv6":          "net.IP",
+	"uuid":          "UUID",
+}
+
+// CommonInitialisms are the initialisms golint expects to be upper cased.
+var CommonInitialisms = []string{
+	"ACL", "API", "ASCII", "CPU", "CSS", "DNS", "EOF", "GUID", "HTML", "HTTP",
+	"HTTPS", "ID", "IP", "JSON", "LHS", "QPS", "RAM", "RHS", "RPC", "SLA",
+	"SMTP", "SQL", "SSH", "TCP", "TLS", "TTL", "UDP", "UI", "UID", "UUID",
+	"URI", "URL", "UTF8", "VM", "XML", "XMPP", "XSRF", "XSS",
+}
+
 // Generator will produce structs from the JSON schema.
 type Generator struct {
 	schemas  []*Schema
@@ -17,38 +44,81 @@ type Generator struct {
 	Structs  map[string]Struct
 	Aliases  map[string]Field
 	OneOfs   map[string]OneOf
+	AnyOfs   map[string]AnyOf
+	Enums    map[string]Enum
+	// Formats maps the string formats to golang types. The properties with a
+	// format that is not in the map are plain strings, as are the "*url.URL"
+	// ones that are not properties, e.g. the items of an array.
+	Formats map[string]string
+	// NumberType is the golang type of the numbers with no "float" or
+	// "double" format: "float64" (the default), "json.Number" or
+	// "decimal.Decimal"
+	NumberType string
+	// Loader loads the referenced documents that are not part of the input
+	// schemas, none are loaded if nil
+	Loader Loader
+	// SchemaKeyRequired is set to true when the loaded documents must have a
+	// $schema key
+	SchemaKeyRequired bool
+	// Initialisms are upper cased in the generated names, e.g. "user_id" is
+	// "UserID" with "ID". None are by default, see CommonInitialisms.
+	Initialisms map[string]bool
 	// cache for reference types; k=url v=type
-	refs      map[string]string
+	refs map[string]string
+	// the documents the types come from; k=type v=root schema id
+	sources   map[string]string
 	anonCount int
 }
 
 // New creates an instance of a generator which will produce structs.
 func New(schemas ...*Schema) *Generator {
+	formats := make(map[string]string, len(DefaultFormats))
+	for k, v := range DefaultFormats {
+		formats[k] = v
+	}
 	return &Generator{
 		schemas:  schemas,
 		resolver: NewRefResolver(schemas),
 		Structs:  make(map[string]Struct),
 		Aliases:  make(map[string]Field),
 		OneOfs:   make(map[string]OneOf),
+		AnyOfs:   make(map[string]AnyOf),
+		Enums:    make(map[string]Enum),
+		Formats:  formats,
+		Loader:   NewDefaultLoader(),
 		refs:     make(map[string]string),
+		sources:  make(map[string]string),
 	}
 }
 
 // CreateTypes creates types from the JSON schemas, keyed by the golang name.
 func (g *Generator) CreateTypes() (err error) {
+	g.resolver.Loader = g.Loader
+	g.resolver.SchemaKeyRequired = g.SchemaKeyRequired
 	if err := g.resolver.Init(); err != nil {
 		return err
 	}
 
 	// extract the types
 	for _, schema := range g.schemas {
+		// an OpenAPI document is not a type by itself
+		if schema.OpenAPI != "" {
+			if err := g.processDefinitions(schema); err != nil {
+				return err
+			}
+			continue
+		}
 		name := g.getSchemaName("", schema)
 		rootType, err := g.processSchema(name, schema)
 		if err != nil {
 			return err
 		}
-		// ugh: if it was anything but a struct the type will not be the name...
-		if rootType != "*"+name {
+		if t, _ := schema.Type(); t == "string" && rootType == g.Formats[schema.Format] {
+			// a type defined from the format type would lose its JSON methods
+			rootType = "string"
+		}
+		// ugh: if it was anything but a struct or an enum the type will not be the name...
+		if rootType != "*"+name && rootType != name {
 			a := Field{
 				Name:        name,
 				JSONName:    "",
@@ -57,22 +127,42 @@ func (g *Generator) CreateTypes() (err error) {
 				Description: schema.Description,
 			}
 			g.Aliases[a.Name] = a
+			g.setSource(a.Name, schema)
 		}
 	}
 
 	return
 }
 
+// setSource records the document a type is generated from
+func (g *Generator) setSource(typeName string, schema *Schema) {
+	g.sources[typeName] = schema.GetRoot().ID()
+}
+
 // process a block of definitions
 func (g *Generator) processDefinitions(schema *Schema) error {
-	for key, subSchema := range schema.Definitions {
-		if _, err := g.processSchema(getGolangName(key), subSchema); err != nil {
-			return err
+	// the definitions are processed in order for the names given on a clash
+	// to be stable
+	for _, definitions := range []map[string]*Schema{schema.Definitions, schema.Defs, schema.ComponentSchemas} {
+		for _, key := range sortedSchemaKeys(definitions) {
+			if _, err := g.processSchema(g.golangName(key), definitions[key]); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
 }
 
+// sortedSchemaKeys returns the sorted keys of a map of schemas
+func sortedSchemaKeys(m map[string]*Schema) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // process a reference string
 func (g *Generator) processReference(schema *Schema) (string, error) {
 	schemaPath := g.resolver.GetPath(schema)
@@ -97,66 +187,123 @@ func (g *Generator) processReference(schema *Schema) (string, error) {
 
 // returns the type refered to by schema after resolving all dependencies
 func (g *Generator) processSchema(schemaName string, schema *Schema) (typ string, err error) {
-	if len(schema.Definitions) > 0 {
-		g.processDefinitions(schema)
+	if schema.GoName != "" {
+		if !token.IsIdentifier(schema.GoName) {
+			return "", fmt.Errorf("invalid x-go-name %q at \"%s\"", schema.GoName, g.resolver.GetPath(schema))
+		}
+		schemaName = schema.GoName
+	}
+	if typeValue, ok := nonNullType(schema); ok {
+		// process the schema as its non null type, the null value of an enum
+		// being the one of the Nullable
+		original, nullable, enum := schema.TypeValue, schema.Nullable, schema.Enum
+		schema.TypeValue, schema.Nullable, schema.Enum = typeValue, false, nonNullValues(schema.Enum)
+		typ, err := g.processSchema(schemaName, schema)
+		schema.TypeValue, schema.Nullable, schema.Enum = original, nullable, enum
+		if err != nil {
+			return "", err
+		}
+		typ = nullableType(typ)
+		if schema.GeneratedType != "" {
+			schema.GeneratedType = typ
+		}
+		return typ, nil
+	}
+	if len(schema.Definitions) > 0 || len(schema.Defs) > 0 {
+		if err := g.processDefinitions(schema); err != nil {
+			return "", err
+		}
 	}
 	schema.FixMissingTypeValue()
-	// if we have multiple schema types, the golang type will be interface{}
+	if len(schema.AllOf) != 0 {
+		return g.processAllOf(schemaName, schema)
+	}
+	if len(schema.Enum) > 1 {
+		return g.processEnum(schemaName, schema)
+	}
 	typ = "interface{}"
 	types, isMultiType := schema.MultiType()
+	if isMultiType {
+		return g.processMultiType(schemaName, schema, types)
+	}
 	if len(types) > 0 {
-		for _, schemaType := range types {
-			name := schemaName
-			if isMultiType {
-				name = name + "_" + schemaType
-			}
-			switch schemaType {
-			case "object":
-				rv, err := g.processObject(name, schema)
-				if err != nil {
-					return "", err
-				}
-				if !isMultiType {
-					return rv, nil
-				}
-			case "array":
-				rv, err := g.processArray(name, schema)
-				if err != nil {
-					return "", err
-				}
-				if !isMultiType {
-					return rv, nil
-				}
-			case "number":
-				if !schema.MultipleOf.IsZero() {
-					exp := schema.MultipleOf.Exponent()
-					if exp < 0 && schema.MultipleOf.Equal(decimal.New(1, exp)) {
-						if !isMultiType {
-							return "decimal.Decimal", nil
-						}
-					}
-				}
-				if !isMultiType {
-					return "float64", nil
-				}
-			default:
-				rv, err := getPrimitiveTypeName(schemaType, "", false)
-				if err != nil {
-					return "", err
-				}
-				if !isMultiType {
-					return rv, nil
+		switch schemaType := types[0]; schemaType {
+		case "object":
+			return g.processObject(schemaName, schema)
+		case "array":
+			return g.processArray(schemaName, schema)
+		case "integer":
+			return getIntegerTypeName(schema), nil
+		case "number":
+			if !schema.MultipleOf.IsZero() {
+				exp := schema.MultipleOf.Exponent()
+				if exp < 0 && schema.MultipleOf.Equal(decimal.New(1, exp)) {
+					return "decimal.Decimal", nil
 				}
 			}
+			return g.getNumberTypeName(schema), nil
+		case "string":
+			// the URLs have no text marshalling, only the struct fields parse
+			// them, see processObject
+			if formatType, ok := g.Formats[schema.Format]; ok && formatType != "*url.URL" {
+				return formatType, nil
+			}
+			return "string", nil
+		default:
+			return getPrimitiveTypeName(schemaType, "", false)
 		}
 	} else if schema.Reference != "" {
 		return g.processReference(schema)
 	} else if len(schema.OneOf) != 0 {
 		return g.processOneOf(schemaName, schema)
+	} else if len(schema.AnyOf) != 0 {
+		return g.processAnyOf(schemaName, schema)
 	}
 	return // return interface{}
 }
 
+// nonNullValues returns the enum values other than null
+func nonNullValues(values []json.RawMessage) []json.RawMessage {
+	var nonNull []json.RawMessage
+	for _, v := range values {
+		if string(bytes.TrimSpace(v)) != "null" {
+			nonNull = append(nonNull, v)
+		}
+	}
+	return nonNull
+}
+
+// nonNullType returns the type value of a schema allowing a null value in
+// addition to a type, e.g. "string" for ["string", "null"] or an OpenAPI
+// nullable string.
+func nonNullType(schema *Schema) (interface{}, bool) {
+	if types, isMultiType := schema.MultiType(); isMultiType && len(types) == 2 && contains(types, "null") {
+		if types[0] == "null" {
+			return types[1], true
+		}
+		return types[0], true
+	}
+	return schema.TypeValue, schema.Nullable
+}
+
+// nullableType returns the Nullable type of typ, that tells an absent value
+// from a null one, holding the structs by value
+func nullableType(typ string) string {
+	if typ == "interface{}" || typ == "nil" {
+		return typ
+	}
+	return "Nullable[" + strings.TrimPrefix(typ, "*") + "]"
+}
+
+// isNullableType returns true if typ is a Nullable type, and the type of its
+// value
+func isNullableType(typ string) (string, bool) {
+	if strings.HasPrefix(typ, "Nullable[") && strings.HasSuffix(typ, "]") {
+		return typ[len("Nullable[") : len(typ)-1], true
+	}
+	return typ, false
+}
+
 func getOneOfTypeNull(typ string) string {
 	switch typ {
 	case "string":
@@ -173,65 +320,626 @@ func getOneOfTypeNull(typ string) string {
 	}
 }
 
-func (g *Generator) generateOneOf(schemaName string, schema *Schema) (string, error) {
-	var oneOf = OneOf{
+// process the sub-schemas of a oneOf or anyOf into union types
+func (g *Generator) processUnionTypes(schemaName string, subSchemas []*Schema) ([]OneOfType, error) {
+	var types []OneOfType
+	for _, subSchema := range subSchemas {
+		typ, err := g.processSchema(schemaName+g.golangName(subSchema.Title), subSchema)
+		if err != nil {
+			return nil, err
+		}
+		// the json type of a reference is the one of the referenced schema
+		jsonType, _ := g.resolveSchema(subSchema).Type()
+		shortType := typ
+		if subSchema.Title != "" {
+			shortType = g.golangName(subSchema.Title)
+		} else if !isNamedType(typ) {
+			// the composite types are named after their JSON type, e.g. "Array"
+			// for "[]string", as the types of processMultiType
+			shortType = "Value"
+			if jsonType != "" {
+				shortType = g.golangName(jsonType)
+			}
+		}
+		unionType := OneOfType{
+			ShortType: unionShortType(shortType),
+			Type:      typ,
+			JSONType:  jsonType,
+		}
+		duplicate := false
+		for _, t := range types {
+			if t.ShortType != unionType.ShortType {
+				continue
+			}
+			if t.Type != unionType.Type {
+				return nil, fmt.Errorf("the sub-schemas of %s have the same name %s for the types %s and %s",
+					schemaName, t.ShortType, t.Type, unionType.Type)
+			}
+			// the sub-schemas of the same type are a single member of the union
+			duplicate = true
+		}
+		if !duplicate {
+			types = append(types, unionType)
+		}
+	}
+	return types, nil
+}
+
+// isNamedType returns true if typ is a named type, possibly qualified or a
+// pointer, e.g. "*decimal.Decimal", and not a composite one, e.g. "[]string"
+func isNamedType(typ string) bool {
+	typ = strings.TrimPrefix(typ, "*")
+	if i := strings.Index(typ, "."); i >= 0 {
+		if !token.IsIdentifier(typ[:i]) {
+			return false
+		}
+		typ = typ[i+1:]
+	}
+	return token.IsIdentifier(typ)
+}
+
+// unionShortType returns the name of a union type in the names of its
+// methods, e.g. "Decimal" for "decimal.Decimal"
+func unionShortType(typ string) string {
+	typ = strings.TrimPrefix(typ, "*")
+	// strip the package of qualified types, e.g. "decimal.Decimal"
+	typ = typ[strings.LastIndex(typ, ".")+1:]
+	return strings.ToUpper(typ[:1]) + typ[1:]
+}
+
+// processMultiType generates a union of the types of a schema with several
+// types, e.g. ["string", "array"], telling them apart by their JSON kind
+func (g *Generator) processMultiType(schemaName string, schema *Schema, types []string) (string, error) {
+	oneOf := OneOf{
 		Name:        g.getSchemaName(schemaName, schema) + "Type",
 		Description: schema.Description,
 	}
-
-	for _, subSchema := range schema.OneOf {
-		typ, err := g.processSchema(schemaName+getGolangName(subSchema.Title), subSchema)
+	// cache the union name in case any sub-schemas recursively reference it
+	schema.GeneratedType = oneOf.Name
+	for _, schemaType := range types {
+		// the schema restricted to one of its types, its definitions are
+		// already processed
+		single := *schema
+		single.TypeValue = schemaType
+		single.GoName = ""
+		single.Definitions = nil
+		single.Defs = nil
+		single.GeneratedType = ""
+		typ, err := g.processSchema(schemaName+g.golangName(schemaType), &single)
 		if err != nil {
 			return "", err
 		}
-		jsonType, _ := subSchema.Type()
 		shortType := typ
-		if subSchema.Title != "" {
-			shortType = getGolangName(subSchema.Title)
-		}
-		if strings.HasPrefix(shortType, "*") {
-			shortType = shortType[1:]
+		if schemaType == "object" || schemaType == "array" {
+			shortType = g.golangName(schemaType)
 		}
-		shortType = strings.ToUpper(shortType[:1]) + shortType[1:]
 		oneOf.Types = append(oneOf.Types, OneOfType{
-			ShortType: shortType,
+			ShortType: unionShortType(shortType),
 			Type:      typ,
-			JSONType:  jsonType,
+			JSONType:  schemaType,
 		})
 	}
 	g.OneOfs[oneOf.Name] = oneOf
+	g.setSource(oneOf.Name, schema)
 	return oneOf.Name, nil
 }
 
+// processNullUnion handles the 2 sub-schemas unions. If one of them is
+// 'null', the union is a nullable version of the other one.
+func (g *Generator) processNullUnion(schemaName string, subSchemas []*Schema) (typ string, ok bool, err error) {
+	if len(subSchemas) != 2 {
+		return "", false, nil
+	}
+	type1, err := g.processSchema(schemaName, subSchemas[0])
+	if err != nil {
+		return "", false, err
+	}
+	if type1 == "interface{}" {
+		return type1, true, nil
+	}
+	type2, err := g.processSchema(schemaName, subSchemas[1])
+	if err != nil {
+		return "", false, err
+	}
+	if type2 == "interface{}" {
+		return type2, true, nil
+	}
+
+	if type1 == "nil" {
+		return getOneOfTypeNull(type2), true, nil
+	} else if type2 == "nil" {
+		return getOneOfTypeNull(type1), true, nil
+	}
+	return "", false, nil
+}
+
+func (g *Generator) generateOneOf(schemaName string, schema *Schema) (string, error) {
+	types, err := g.processUnionTypes(schemaName, schema.OneOf)
+	if err != nil {
+		return "", err
+	}
+	var oneOf = OneOf{
+		Name:        g.getSchemaName(schemaName, schema) + "Type",
+		Description: schema.Description,
+		Types:       types,
+	}
+	if err := g.processDiscriminator(schema, &oneOf); err != nil {
+		return "", err
+	}
+	g.OneOfs[oneOf.Name] = oneOf
+	g.setSource(oneOf.Name, schema)
+	return oneOf.Name, nil
+}
+
+// resolveSchema returns the schema a reference points to, or the schema
+// itself if it is not a reference
+func (g *Generator) resolveSchema(schema *Schema) *Schema {
+	for schema.Reference != "" {
+		refSchema, err := g.resolver.GetSchemaByReference(schema)
+		if err != nil || refSchema == schema {
+			break
+		}
+		schema = refSchema
+	}
+	return schema
+}
+
+// discriminatorValue returns the string a property is restricted to by a
+// 'const' or a single value 'enum'
+func discriminatorValue(prop *Schema) (string, bool) {
+	raw := prop.Const
+	if raw == nil && len(prop.Enum) == 1 {
+		raw = prop.Enum[0]
+	}
+	var value string
+	if raw == nil || json.Unmarshal(raw, &value) != nil {
+		return "", false
+	}
+	return value, true
+}
+
+// referenceName returns the last element of a reference, i.e. the name of the
+// referenced definition
+func referenceName(ref string) string {
+	return ref[strings.LastIndexAny(ref, "/#")+1:]
+}
+
+// processDiscriminator finds the property telling which object type a oneOf
+// value is, either from the 'discriminator' keyword or from a property that
+// has a distinct constant value in each object sub-schema
+func (g *Generator) processDiscriminator(schema *Schema, oneOf *OneOf) error {
+	branches := make([]*Schema, len(schema.OneOf))
+	for i, subSchema := range schema.OneOf {
+		branches[i] = g.resolveSchema(subSchema)
+	}
+
+	if d := schema.Discriminator; d != nil && d.PropertyName != "" {
+		for i, subSchema := range schema.OneOf {
+			if oneOf.Types[i].JSONType != "object" {
+				continue
+			}
+			var values []string
+			for value, ref := range d.Mapping {
+				if (subSchema.Reference != "" && ref == referenceName(subSchema.Reference)) ||
+					g.resolveSchema(&Schema{Reference: ref, Parent: schema}) == branches[i] {
+					values = append(values, value)
+				}
+			}
+			if len(values) == 0 {
+				if prop, ok := branches[i].Properties[d.PropertyName]; ok {
+					if value, ok := discriminatorValue(prop); ok {
+						values = append(values, value)
+					}
+				}
+			}
+			if len(values) == 0 && subSchema.Reference != "" {
+				values = append(values, referenceName(subSchema.Reference))
+			}
+			if len(values) == 0 {
+				return fmt.Errorf("processDiscriminator: no \"%s\" value for oneOf type %s at \"%s\"",
+					d.PropertyName, oneOf.Types[i].Type, g.resolver.GetPath(schema))
+			}
+			sort.Strings(values)
+			oneOf.Types[i].DiscriminatorValues = values
+		}
+		oneOf.Discriminator = d.PropertyName
+		return nil
+	}
+
+	// look for a property with a distinct constant value in all the object types
+	var objects []int
+	for i, t := range oneOf.Types {
+		if t.JSONType == "object" {
+			objects = append(objects, i)
+		}
+	}
+	if len(objects) < 2 {
+		return nil
+	}
+	candidates := make([]string, 0, len(branches[objects[0]].Properties))
+	for propName := range branches[objects[0]].Properties {
+		candidates = append(candidates, propName)
+	}
+	sort.Strings(candidates)
+candidates:
+	for _, propName := range candidates {
+		values := make([]string, len(objects))
+		for n, i := range objects {
+			prop, ok := branches[i].Properties[propName]
+			if !ok {
+				continue candidates
+			}
+			value, ok := discriminatorValue(prop)
+			if !ok || contains(values[:n], value) {
+				continue candidates
+			}
+			values[n] = value
+		}
+		for n, i := range objects {
+			oneOf.Types[i].DiscriminatorValues = []string{values[n]}
+		}
+		oneOf.Discriminator = propName
+		return nil
+	}
+	return nil
+}
+
 func (g *Generator) processOneOf(schemaName string, schema *Schema) (typ string, err error) {
-	if len(schema.OneOf) == 2 {
-		type1, err := g.processSchema(schemaName, schema.OneOf[0])
+	if typ, ok, err := g.processNullUnion(schemaName, schema.OneOf); err != nil || ok {
+		return typ, err
+	}
+	return g.generateOneOf(schemaName, schema)
+}
+
+func (g *Generator) generateAnyOf(schemaName string, schema *Schema) (string, error) {
+	types, err := g.processUnionTypes(schemaName, schema.AnyOf)
+	if err != nil {
+		return "", err
+	}
+	var anyOf = AnyOf{
+		Name:        g.getSchemaName(schemaName, schema) + "Type",
+		Description: schema.Description,
+		Types:       types,
+	}
+	g.AnyOfs[anyOf.Name] = anyOf
+	g.setSource(anyOf.Name, schema)
+	return anyOf.Name, nil
+}
+
+func (g *Generator) processAnyOf(schemaName string, schema *Schema) (typ string, err error) {
+	if typ, ok, err := g.processNullUnion(schemaName, schema.AnyOf); err != nil || ok {
+		return typ, err
+	}
+	return g.generateAnyOf(schemaName, schema)
+}
+
+// isObjectSchema returns true if the schema describes an object
+func isObjectSchema(schema *Schema) bool {
+	types, _ := schema.MultiType()
+	return contains(types, "object") || len(schema.Properties) != 0 || len(schema.PatternProperties) != 0 ||
+		schema.AdditionalProperties != nil
+}
+
+// allOfBranches returns the sub-schemas of an allOf, with the references
+// resolved and the nested allOf flattened
+func (g *Generator) allOfBranches(schema *Schema, visited map[*Schema]bool) ([]*Schema, error) {
+	var branches []*Schema
+	for _, subSchema := range schema.AllOf {
+		if subSchema.Reference != "" {
+			refSchema, err := g.resolver.GetSchemaByReference(subSchema)
+			if err != nil {
+				return nil, errors.New("processAllOf: reference \"" + subSchema.Reference + "\" not found at \"" + g.resolver.GetPath(subSchema) + "\"")
+			}
+			subSchema = refSchema
+		}
+		if visited[subSchema] {
+			continue
+		}
+		visited[subSchema] = true
+		branches = append(branches, subSchema)
+		subBranches, err := g.allOfBranches(subSchema, visited)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
-		if type1 == "interface{}" {
-			return type1, nil
+		branches = append(branches, subBranches...)
+	}
+	return branches, nil
+}
+
+// processAllOf merges the allOf sub-schemas into a single type
+func (g *Generator) processAllOf(schemaName string, schema *Schema) (string, error) {
+	branches, err := g.allOfBranches(schema, map[*Schema]bool{schema: true})
+	if err != nil {
+		return "", err
+	}
+	all := append([]*Schema{schema}, branches...)
+
+	isObject := false
+	for _, s := range all {
+		if isObjectSchema(s) {
+			isObject = true
+			break
 		}
-		type2, err := g.processSchema(schemaName, schema.OneOf[1])
+	}
+	if !isObject {
+		// nothing to merge, the branches must agree on a single type
+		own := *schema
+		own.AllOf = nil
+		typ, err := g.processSchema(schemaName, &own)
 		if err != nil {
 			return "", err
 		}
-		if type2 == "interface{}" {
-			return type2, nil
+		for _, subSchema := range schema.AllOf {
+			subTyp, err := g.processSchema(schemaName, subSchema)
+			if err != nil {
+				return "", err
+			}
+			if typ == "interface{}" {
+				typ = subTyp
+			} else if subTyp != "interface{}" && subTyp != typ {
+				return "", fmt.Errorf("processAllOf: incompatible types %s and %s at \"%s\"",
+					typ, subTyp, g.resolver.GetPath(schema))
+			}
 		}
+		return typ, nil
+	}
+
+	// cache the object name in case any sub-schemas recursively reference it
+	schema.GeneratedType = "*" + schemaName
 
-		if type1 == "nil" {
-			return getOneOfTypeNull(type2), nil
-		} else if type2 == "nil" {
-			return getOneOfTypeNull(type1), nil
+	merged := &Schema{
+		ID04:        schema.ID04,
+		ID06:        schema.ID06,
+		Title:       schema.Title,
+		Description: schema.Description,
+		TypeValue:   "object",
+		Properties:  make(map[string]*Schema),
+		Parent:      schema.Parent,
+		JSONKey:     schema.JSONKey,
+		PathElement: schema.PathElement,
+	}
+	for _, s := range all {
+		if merged.Description == "" {
+			merged.Description = s.Description
+		}
+		for propKey, prop := range s.Properties {
+			if existing, ok := merged.Properties[propKey]; ok && existing != prop {
+				keep, err := g.mergeAllOfProperty(schema, propKey, existing, prop)
+				if err != nil {
+					return "", err
+				}
+				merged.Properties[propKey] = keep
+				continue
+			}
+			merged.Properties[propKey] = prop
+		}
+		for pattern, prop := range s.PatternProperties {
+			if merged.PatternProperties == nil {
+				merged.PatternProperties = make(map[string]*Schema)
+			}
+			if _, ok := merged.PatternProperties[pattern]; !ok {
+				merged.PatternProperties[pattern] = prop
+			}
+		}
+		for _, r := range s.Required {
+			if !contains(merged.Required, r) {
+				merged.Required = append(merged.Required, r)
+			}
 		}
 	}
-	return g.generateOneOf(schemaName, schema)
+	merged.AdditionalProperties, err = g.mergeAllOfAdditionalProperties(schema, all)
+	if err != nil {
+		return "", err
+	}
+	// the conditional keywords of all the sub-schemas apply to the merged object
+	merged.AllOf = all
+
+	typ, err := g.processObject(schemaName, merged)
+	if err != nil {
+		return "", err
+	}
+	schema.GeneratedType = merged.GeneratedType
+	return typ, nil
+}
+
+// mergeAllOfProperty checks that a property declared by several allOf
+// branches has compatible types, and returns the schema to use for it
+func (g *Generator) mergeAllOfProperty(schema *Schema, propKey string, existing, prop *Schema) (*Schema, error) {
+	fieldName := g.fieldName(propKey, prop)
+	existingTyp, err := g.processSchema(g.getSchemaName(fieldName, existing), existing)
+	if err != nil {
+		return nil, err
+	}
+	propTyp, err := g.processSchema(g.getSchemaName(fieldName, prop), prop)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case propTyp == existingTyp, propTyp == "interface{}":
+		return existing, nil
+	case existingTyp == "interface{}":
+		return prop, nil
+	}
+	return nil, fmt.Errorf("processAllOf: property \"%s\" at \"%s\" is declared with incompatible types %s and %s",
+		propKey, g.resolver.GetPath(schema), existingTyp, propTyp)
+}
+
+// mergeAllOfAdditionalProperties merges the additionalProperties of the allOf
+// branches. 'false' wins over everything else, and typed sub-schemas must agree.
+func (g *Generator) mergeAllOfAdditionalProperties(schema *Schema, all []*Schema) (*AdditionalProperties, error) {
+	var rv *AdditionalProperties
+	var rvTyp string
+	for _, s := range all {
+		ap := s.AdditionalProperties
+		if ap == nil {
+			continue
+		}
+		if ap.AdditionalPropertiesBool != nil {
+			if !*ap.AdditionalPropertiesBool {
+				return ap, nil
+			}
+			if rv == nil {
+				rv = ap
+			}
+			continue
+		}
+		if rv == nil || rv.AdditionalPropertiesBool != nil {
+			rv = ap
+			rvTyp = ""
+			continue
+		}
+		var err error
+		if rvTyp == "" {
+			rvTyp, err = g.processSchema(g.getSchemaName("", (*Schema)(rv)), (*Schema)(rv))
+			if err != nil {
+				return nil, err
+			}
+		}
+		typ, err := g.processSchema(g.getSchemaName("", (*Schema)(ap)), (*Schema)(ap))
+		if err != nil {
+			return nil, err
+		}
+		if typ != rvTyp {
+			return nil, fmt.Errorf("processAllOf: additionalProperties at \"%s\" are declared with incompatible types %s and %s",
+				g.resolver.GetPath(schema), rvTyp, typ)
+		}
+	}
+	return rv, nil
+}
+
+// enumValueKind returns the kind of a raw JSON enum value: "string",
+// "integer", "number" or "" for anything else
+func enumValueKind(raw json.RawMessage) string {
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return ""
+	}
+	switch v.(type) {
+	case string:
+		return "string"
+	case float64:
+		if strings.ContainsAny(string(raw), ".eE") {
+			return "number"
+		}
+		return "integer"
+	}
+	return ""
+}
+
+// processEnum generates a named type with a constant for each enum value
+func (g *Generator) processEnum(name string, schema *Schema) (typ string, err error) {
+	enum := Enum{
+		Name:        name,
+		Description: schema.Description,
+	}
+	kind := ""
+	for i, raw := range schema.Enum {
+		k := enumValueKind(raw)
+		switch {
+		case i == 0:
+			kind = k
+		case k == "number" && kind == "integer", k == "integer" && kind == "number":
+			kind = "number"
+		case k != kind:
+			kind = ""
+		}
+	}
+	switch kind {
+	case "string":
+		enum.Type = "string"
+	case "integer":
+		enum.Type = "int"
+	case "number":
+		enum.Type = "float64"
+	default:
+		// mixed enums hold the JSON encoding of their value
+		enum.Type = "string"
+		enum.Mixed = true
+	}
+
+	var seen []string
+	for _, raw := range schema.Enum {
+		buf := new(bytes.Buffer)
+		if err := json.Compact(buf, raw); err != nil {
+			return "", fmt.Errorf("processEnum: invalid value %s at \"%s\": %v", raw, g.resolver.GetPath(schema), err)
+		}
+		value := buf.String()
+		if contains(seen, value) {
+			continue
+		}
+		seen = append(seen, value)
+
+		var literal, suffix string
+		switch kind {
+		case "string":
+			var str string
+			json.Unmarshal(raw, &str)
+			literal = strconv.Quote(str)
+			suffix = str
+		case "integer", "number":
+			literal = value
+			suffix = strings.NewReplacer("-", "Minus", ".", "Dot", "+", "").Replace(value)
+		default:
+			literal = strconv.Quote(value)
+			suffix = strings.Trim(value, `"`)
+		}
+		suffix = strings.TrimPrefix(g.golangName(suffix), "_")
+		if suffix == "" {
+			suffix = "Empty"
+		}
+		constName := name + suffix
+		for i := 2; enum.hasConst(constName); i++ {
+			constName = fmt.Sprintf("%s%s%d", name, suffix, i)
+		}
+		enum.Values = append(enum.Values, EnumValue{
+			Name:    constName,
+			Literal: literal,
+		})
+	}
+	if existing, ok := g.Enums[enum.Name]; ok && !reflect.DeepEqual(existing, enum) {
+		enum = g.renameEnum(enum, schema)
+	}
+	g.Enums[enum.Name] = enum
+	g.setSource(enum.Name, schema)
+	return enum.Name, nil
+}
+
+// renameEnum names an enum clashing with another one after the type of its
+// parent object, e.g. "OrderStatus", or with a number if it is taken too
+func (g *Generator) renameEnum(enum Enum, schema *Schema) Enum {
+	parentName := ""
+	for parent := schema.Parent; parent != nil; parent = parent.Parent {
+		if parent.GeneratedType != "" {
+			parentName = strings.TrimPrefix(parent.GeneratedType, "*")
+			break
+		}
+	}
+	name := parentName + enum.Name
+	for i := 2; ; i++ {
+		if name != enum.Name {
+			renamed := enum
+			renamed.Name = name
+			renamed.Values = make([]EnumValue, len(enum.Values))
+			for j, v := range enum.Values {
+				renamed.Values[j] = EnumValue{
+					Name:    name + strings.TrimPrefix(v.Name, enum.Name),
+					Literal: v.Literal,
+				}
+			}
+			if existing, ok := g.Enums[name]; !ok || reflect.DeepEqual(existing, renamed) {
+				return renamed
+			}
+		}
+		name = fmt.Sprintf("%s%s%d", parentName, enum.Name, i)
+	}
 }
 
 // name: name of this array, usually the js key
 // schema: items element
 func (g *Generator) processArray(name string, schema *Schema) (typeStr string, err error) {
+	if len(schema.PrefixItems) > 0 {
+		return g.processTuple(name, schema)
+	}
 	if schema.Items != nil {
 		// subType: fallback name in case this array contains inline object without a title
 		subName := g.getSchemaName(name+"Items", schema.Items)
@@ -253,12 +961,82 @@ func (g *Generator) processArray(name string, schema *Schema) (typeStr string, e
 				Description: schema.Description,
 			}
 			g.Aliases[array.Name] = array
+			g.setSource(array.Name, schema)
 		}
 		return finalType, nil
 	}
 	return "[]interface{}", nil
 }
 
+// processTuple generates a struct for a tuple array, its fields being the
+// items at each position
+func (g *Generator) processTuple(name string, schema *Schema) (typ string, err error) {
+	strct := Struct{
+		ID:           schema.ID(),
+		Name:         name,
+		Description:  schema.Description,
+		Fields:       make(map[string]Field, len(schema.PrefixItems)),
+		GenerateCode: true,
+	}
+	// cache the tuple name in case any sub-schemas recursively reference it
+	schema.GeneratedType = "*" + name
+	minItems := 0
+	if schema.MinItems != nil {
+		minItems = *schema.MinItems
+	}
+	for i, item := range schema.PrefixItems {
+		fieldName := fmt.Sprintf("Item%d", i)
+		if item.GoName != "" {
+			fieldName = item.GoName
+		} else if item.Title != "" {
+			fieldName = g.golangName(item.Title)
+		}
+		if _, ok := strct.Fields[fieldName]; ok {
+			fieldName = fmt.Sprintf("%s%d", fieldName, i)
+		}
+		fieldType, err := g.processSchema(name+fieldName, item)
+		if err != nil {
+			return "", err
+		}
+		strct.Fields[fieldName] = Field{
+			Name:        fieldName,
+			JSONName:    strconv.Itoa(i),
+			Type:        fieldType,
+			Required:    i < minItems,
+			Description: item.Description,
+			Validation:  newValidation(item),
+		}
+		strct.TupleFields = append(strct.TupleFields, fieldName)
+	}
+	// the items after the tuple ones
+	if schema.AdditionalItems != nil {
+		subTyp, err := g.processSchema(name+"AdditionalItems", schema.AdditionalItems)
+		if err != nil {
+			return "", err
+		}
+		strct.Fields["AdditionalItems"] = Field{
+			Name:     "AdditionalItems",
+			JSONName: "-",
+			Type:     "[]" + subTyp,
+		}
+		strct.AdditionalItems = subTyp
+	} else if schema.AdditionalItemsBool != nil {
+		if *schema.AdditionalItemsBool {
+			strct.Fields["AdditionalItems"] = Field{
+				Name:     "AdditionalItems",
+				JSONName: "-",
+				Type:     "[]interface{}",
+			}
+			strct.AdditionalItems = "interface{}"
+		} else {
+			strct.AdditionalItems = "false"
+		}
+	}
+	g.Structs[strct.Name] = strct
+	g.setSource(strct.Name, schema)
+	return "*" + name, nil
+}
+
 // name: name of the struct (calculated by caller)
 // schema: detail incl properties & child objects
 // returns: generated type
@@ -271,30 +1049,112 @@ func (g *Generator) processObject(name string, schema *Schema) (typ string, err
 	}
 	// cache the object name in case any sub-schemas recursively reference it
 	schema.GeneratedType = "*" + name
-	// regular properties
+	// the properties of the conditional sub-schemas are optional fields
+	properties := make(map[string]*Schema, len(schema.Properties))
 	for propKey, prop := range schema.Properties {
-		fieldName := getGolangName(propKey)
+		properties[propKey] = prop
+	}
+	for _, conditional := range g.conditionalSchemas(schema) {
+		for propKey, prop := range conditional.Properties {
+			if _, ok := properties[propKey]; !ok {
+				properties[propKey] = prop
+			}
+		}
+	}
+	// regular properties
+	for _, propKey := range sortedSchemaKeys(properties) {
+		prop := properties[propKey]
+		fieldName := g.fieldName(propKey, prop)
 		// calculate sub-schema name here, may not actually be used depending on type of schema!
 		subSchemaName := g.getSchemaName(fieldName, prop)
 		fieldType, err := g.processSchema(subSchemaName, prop)
 		if err != nil {
 			return "", err
 		}
+		if fieldType == "string" && g.Formats[g.resolveSchema(prop).Format] == "*url.URL" {
+			fieldType = "*url.URL"
+		}
 		f := Field{
-			Name:        fieldName,
-			JSONName:    propKey,
-			Type:        fieldType,
-			Required:    contains(schema.Required, propKey),
+			Name:     fieldName,
+			JSONName: propKey,
+			Type:     fieldType,
+			// the read only and write only properties are missing from either
+			// the requests or the responses
+			Required:    contains(schema.Required, propKey) && !prop.ReadOnly && !prop.WriteOnly,
 			Description: prop.Description,
+			Validation:  newValidation(prop),
+			ReadOnly:    prop.ReadOnly,
+			WriteOnly:   prop.WriteOnly,
+			Tags:        prop.GoTags,
+		}
+		if keys, err := structTagKeys(f.Tags); err != nil {
+			return "", fmt.Errorf("invalid x-go-tags of the %s property: %v", propKey, err)
+		} else if contains(keys, "json") {
+			return "", fmt.Errorf("invalid x-go-tags of the %s property: the json tag cannot be set", propKey)
 		}
 		for _, v := range prop.Enum {
 			f.Enum = append(f.Enum, string(v))
 		}
-		if f.Required {
+		// the absent nullable values and unions are omitted by the generated
+		// code only
+		_, nullable := isNullableType(f.Type)
+		_, oneOf := g.OneOfs[f.Type]
+		_, anyOf := g.AnyOfs[f.Type]
+		if f.Required || nullable || oneOf || anyOf {
 			strct.GenerateCode = true
 		}
 		strct.Fields[f.Name] = f
 	}
+	// If this object is inline property for another object, and only contains additional or pattern properties, we
+	// can collapse the structure down to a map.
+	//
+	// If this object is a definition and only contains additional properties, we can't do that or we end up with
+	// no struct
+	isDefinitionObject := strings.HasPrefix(schema.PathElement, "definitions") ||
+		strings.HasPrefix(schema.PathElement, "$defs") ||
+		strings.HasPrefix(schema.PathElement, "components/schemas")
+	// patternProperties, the properties matching each regular expression are in their own map
+	patterns := make([]string, 0, len(schema.PatternProperties))
+	for pattern := range schema.PatternProperties {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return "", fmt.Errorf("invalid patternProperties regular expression %q at \"%s\": %v",
+				pattern, g.resolver.GetPath(schema), err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for i, pattern := range patterns {
+		pp := schema.PatternProperties[pattern]
+		ppName := g.getSchemaName("", pp)
+		fieldName := "PatternProperties"
+		if len(patterns) > 1 {
+			ppName += strconv.Itoa(i + 1)
+			fieldName += strconv.Itoa(i + 1)
+		}
+		if pp.GoName != "" {
+			fieldName = pp.GoName
+		}
+		subTyp, err := g.processSchema(ppName, pp)
+		if err != nil {
+			return "", err
+		}
+		mapTyp := "map[string]" + subTyp
+		if len(properties) == 0 && len(patterns) == 1 && schema.AdditionalProperties == nil && !isDefinitionObject {
+			// the properties can only be the pattern ones
+			return mapTyp, nil
+		}
+		strct.Fields[fieldName] = Field{
+			Name:     fieldName,
+			JSONName: "-",
+			Type:     mapTyp,
+		}
+		strct.PatternProperties = append(strct.PatternProperties, PatternProperty{
+			Pattern: pattern,
+			Name:    fieldName,
+			Type:    subTyp,
+		})
+		strct.GenerateCode = true
+	}
 	// additionalProperties with typed sub-schema
 	if schema.AdditionalProperties != nil && schema.AdditionalProperties.AdditionalPropertiesBool == nil {
 		ap := (*Schema)(schema.AdditionalProperties)
@@ -304,13 +1164,7 @@ func (g *Generator) processObject(name string, schema *Schema) (typ string, err
 			return "", err
 		}
 		mapTyp := "map[string]" + subTyp
-		// If this object is inline property for another object, and only contains additional properties, we can
-		// collapse the structure down to a map.
-		//
-		// If this object is a definition and only contains additional properties, we can't do that or we end up with
-		// no struct
-		isDefinitionObject := strings.HasPrefix(schema.PathElement, "definitions")
-		if len(schema.Properties) == 0 && !isDefinitionObject {
+		if len(properties) == 0 && len(patterns) == 0 && !isDefinitionObject {
 			// since there are no regular properties, we don't need to emit a struct for this object - return the
 			// additionalProperties map type.
 			return mapTyp, nil
@@ -350,11 +1204,107 @@ func (g *Generator) processObject(name string, schema *Schema) (typ string, err
 			strct.AdditionalType = "false"
 		}
 	}
+	strct.Conditions = g.conditions(schema)
 	g.Structs[strct.Name] = strct
+	g.setSource(strct.Name, schema)
 	// objects are always a pointer
 	return getPrimitiveTypeName("object", name, true)
 }
 
+// conditionalSchemas returns the if, then, else and dependentSchemas
+// sub-schemas of an object, and of its allOf sub-schemas once merged
+func (g *Generator) conditionalSchemas(schema *Schema) []*Schema {
+	var schemas []*Schema
+	for _, owner := range append([]*Schema{schema}, schema.AllOf...) {
+		for _, conditional := range owner.conditionalSchemas() {
+			schemas = append(schemas, g.resolveSchema(conditional))
+		}
+	}
+	return schemas
+}
+
+// conditions returns the properties required by the if/then/else,
+// dependentRequired and dependentSchemas keywords of an object, and of its
+// allOf sub-schemas once merged
+func (g *Generator) conditions(schema *Schema) []Condition {
+	var conditions []Condition
+	for _, owner := range append([]*Schema{schema}, schema.AllOf...) {
+		if owner.If != nil {
+			// the if sub-schemas that cannot be checked are skipped
+			if condition, ok := ifCondition(g.resolveSchema(owner.If)); ok {
+				if owner.Then != nil && len(g.resolveSchema(owner.Then).Required) != 0 {
+					then := condition
+					then.Required = g.resolveSchema(owner.Then).Required
+					conditions = append(conditions, then)
+				}
+				if owner.Else != nil && len(g.resolveSchema(owner.Else).Required) != 0 {
+					els := condition
+					els.Unless = true
+					els.Required = g.resolveSchema(owner.Else).Required
+					conditions = append(conditions, els)
+				}
+			}
+		}
+
+		properties := make([]string, 0, len(owner.DependentRequired))
+		for property := range owner.DependentRequired {
+			properties = append(properties, property)
+		}
+		sort.Strings(properties)
+		for _, property := range properties {
+			conditions = append(conditions, Condition{
+				Present:  []string{property},
+				Required: owner.DependentRequired[property],
+			})
+		}
+
+		properties = properties[:0]
+		for property := range owner.DependentSchemas {
+			properties = append(properties, property)
+		}
+		sort.Strings(properties)
+		for _, property := range properties {
+			if required := g.resolveSchema(owner.DependentSchemas[property]).Required; len(required) != 0 {
+				conditions = append(conditions, Condition{
+					Present:  []string{property},
+					Required: required,
+				})
+			}
+		}
+	}
+	return conditions
+}
+
+// ifCondition returns the condition of an if sub-schema made of required
+// properties and of properties having a const or an enum, false if it has
+// other keywords
+func ifCondition(schema *Schema) (Condition, bool) {
+	if schema.Reference != "" || len(schema.AllOf) != 0 || len(schema.AnyOf) != 0 || len(schema.OneOf) != 0 ||
+		schema.If != nil || len(schema.PatternProperties) != 0 {
+		return Condition{}, false
+	}
+	condition := Condition{Present: schema.Required}
+	for propKey, prop := range schema.Properties {
+		values := prop.Enum
+		if prop.Const != nil {
+			values = []json.RawMessage{prop.Const}
+		}
+		if len(values) == 0 {
+			return Condition{}, false
+		}
+		if condition.Values == nil {
+			condition.Values = make(map[string][]string, len(schema.Properties))
+		}
+		for _, v := range values {
+			condition.Values[propKey] = append(condition.Values[propKey], string(v))
+		}
+	}
+	if len(condition.Present) == 0 && len(condition.Values) == 0 {
+		return Condition{}, false
+	}
+	return condition, true
+}
+
 func contains(s []string, e string) bool {
 	for _, a := range s {
 		if a == e {
@@ -364,6 +1314,55 @@ func contains(s []string, e string) bool {
 	return false
 }
 
+var (
+	minInt32  = decimal.New(math.MinInt32, 0)
+	maxInt32  = decimal.New(math.MaxInt32, 0)
+	maxUint32 = decimal.New(math.MaxUint32, 0)
+)
+
+// getIntegerTypeName returns the golang integer type of a schema: the one of
+// its format if any, else one from its bounds, unsigned if the minimum is not
+// negative.
+func getIntegerTypeName(schema *Schema) string {
+	unsigned := schema.Minimum != nil && !schema.Minimum.IsNegative()
+	var typ string
+	switch schema.Format {
+	case "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		// the format wins over the bounds
+		typ = strings.TrimPrefix(schema.Format, "u")
+		unsigned = typ != schema.Format
+	default:
+		// int may only have 32 bits, use 64 bits if the bounds do not fit
+		typ = "int"
+		if unsigned {
+			if schema.Maximum != nil && schema.Maximum.GreaterThan(maxUint32) {
+				typ = "int64"
+			}
+		} else if (schema.Minimum != nil && schema.Minimum.LessThan(minInt32)) ||
+			(schema.Maximum != nil && schema.Maximum.GreaterThan(maxInt32)) {
+			typ = "int64"
+		}
+	}
+	if unsigned {
+		return "u" + typ
+	}
+	return typ
+}
+
+// getNumberTypeName returns the golang type of a number schema
+func (g *Generator) getNumberTypeName(schema *Schema) string {
+	switch schema.Format {
+	case "float":
+		return "float32"
+	case "double":
+		return "float64"
+	}
+	if g.NumberType != "" {
+		return g.NumberType
+	}
+	return "float64"
+}
+
 func getPrimitiveTypeName(schemaType string, subType string, pointer bool) (name string, err error) {
 	switch schemaType {
 	case "array":
@@ -397,20 +1396,23 @@ func getPrimitiveTypeName(schemaType string, subType string, pointer bool) (name
 
 // return a name for this (sub-)schema.
 func (g *Generator) getSchemaName(keyName string, schema *Schema) string {
+	if schema.GoName != "" {
+		return schema.GoName
+	}
 	if len(schema.Title) > 0 {
-		return getGolangName(schema.Title)
+		return g.golangName(schema.Title)
 	}
 	if keyName != "" {
-		return getGolangName(keyName)
+		return g.golangName(keyName)
 	}
 	if schema.Parent == nil {
 		return "Root"
 	}
 	if schema.JSONKey != "" {
-		return getGolangName(schema.JSONKey)
+		return g.golangName(schema.JSONKey)
 	}
 	if schema.Parent != nil && schema.Parent.JSONKey != "" {
-		return getGolangName(schema.Parent.JSONKey + "Item")
+		return g.golangName(schema.Parent.JSONKey + "Item")
 	}
 	g.anonCount++
 	return fmt.Sprintf("Anonymous%d", g.anonCount)
@@ -432,6 +1434,36 @@ func getGolangName(s string) string {
 	return buf.String()
 }
 
+// golangName returns the golang name of s, with the Initialisms upper cased.
+func (g *Generator) golangName(s string) string {
+	if len(g.Initialisms) == 0 || s == "__type__" {
+		return getGolangName(s)
+	}
+	buf := bytes.NewBuffer([]byte{})
+	for i, v := range splitOnAll(s, isNotAGoNameCharacter) {
+		if i == 0 && strings.IndexAny(v, "0123456789") == 0 {
+			// Go types are not allowed to start with a number, lets prefix with an underscore.
+			buf.WriteRune('_')
+		}
+		for _, word := range splitWords(v) {
+			if g.Initialisms[strings.ToUpper(word)] {
+				word = strings.ToUpper(word)
+			}
+			buf.WriteString(capitaliseFirstLetter(word))
+		}
+	}
+	return buf.String()
+}
+
+// fieldName returns the golang name of the field of a property, forced by
+// its x-go-name if any.
+func (g *Generator) fieldName(propKey string, prop *Schema) string {
+	if prop.GoName != "" {
+		return prop.GoName
+	}
+	return g.golangName(propKey)
+}
+
 func splitOnAll(s string, shouldSplit func(r rune) bool) []string {
 	rv := []string{}
 	buf := bytes.NewBuffer([]byte{})
@@ -477,6 +1509,44 @@ type Struct struct {
 
 	GenerateCode   bool
 	AdditionalType string
+	// TupleFields are the fields of the items of a tuple array, in order
+	TupleFields []string
+	// AdditionalItems is the type of the items after the TupleFields, "false"
+	// if there cannot be any
+	AdditionalItems string
+	// Conditions are the properties required when others are present, or
+	// have some values
+	Conditions []Condition
+	// PatternProperties are the maps of the properties matching a
+	// patternProperties regular expression, in the order they are matched
+	PatternProperties []PatternProperty
+}
+
+// Condition is a requirement of properties when other properties are
+// present, or have some values, from the if/then/else, dependentRequired and
+// dependentSchemas keywords.
+type Condition struct {
+	// Present are the JSON names of the properties that must be present
+	Present []string
+	// Values are the JSON values, one of which a property must have, by the
+	// JSON name of the property
+	Values map[string][]string
+	// Unless negates the condition, for the else keyword
+	Unless bool
+	// Required are the JSON names of the properties required when the
+	// condition holds
+	Required []string
+}
+
+// PatternProperty is a map field of the properties whose name matches a
+// regular expression.
+type PatternProperty struct {
+	// The regular expression, e.g. "^[a-z]{2}$"
+	Pattern string
+	// The golang name of the map field
+	Name string
+	// The golang type of the values
+	Type string
 }
 
 // Field defines the data required to generate a field in Go.
@@ -492,6 +1562,14 @@ type Field struct {
 	// Required is set to true when the field is required.
 	Required    bool
 	Description string
+	// Validation holds the validation keywords of the field, if any
+	Validation *Validation
+	// ReadOnly and WriteOnly are set when the field is only sent in
+	// responses or in requests.
+	ReadOnly  bool
+	WriteOnly bool
+	// Tags are extra struct tags of the field, from the x-go-tags extension
+	Tags string
 }
 
 // OneOfType is a type in a OneOf
@@ -499,6 +1577,9 @@ type OneOfType struct {
 	ShortType string
 	Type      string
 	JSONType  string
+	// DiscriminatorValues are the values of the discriminator property
+	// selecting this type
+	DiscriminatorValues []string
 }
 
 // OneOf is a generated type for holding a oneOf field data
@@ -506,6 +1587,44 @@ type OneOf struct {
 	Name        string
 	Description string
 	Types       []OneOfType
+	// Discriminator is the name of the property selecting the object type, if any
+	Discriminator string
+}
+
+// EnumValue is a constant of a generated enum type
+type EnumValue struct {
+	// The golang name of the constant, e.g. "StatusActive"
+	Name string
+	// The golang literal of the value, e.g. "\"active\""
+	Literal string
+}
+
+// Enum is a generated type for holding an enum value
+type Enum struct {
+	Name        string
+	Description string
+	// The underlying golang type: "string", "int" or "float64"
+	Type string
+	// Mixed is true if the values have different JSON types. The value is then
+	// stored as its JSON encoding.
+	Mixed  bool
+	Values []EnumValue
+}
+
+func (e Enum) hasConst(name string) bool {
+	for _, v := range e.Values {
+		if v.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// AnyOf is a generated type for holding an anyOf field data
+type AnyOf struct {
+	Name        string
+	Description string
+	Types       []OneOfType
 }
 
 // GetByJSONType returns the type matching the given json type
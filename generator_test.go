@@ -2,9 +2,13 @@ package generate
 
 import (
 	"encoding/json"
+	"math"
+	"net/url"
 	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/shopspring/decimal"
 )
 
 func TestThatCapitalisationOccursCorrectly(t *testing.T) {
@@ -592,7 +596,7 @@ func TestThatArraysWithoutDefinedItemTypesAreGeneratedAsEmptyInterfaces(t *testi
 	}
 }
 
-func TestThatTypesWithMultipleDefinitionsAreGeneratedAsEmptyInterfaces(t *testing.T) {
+func TestThatTypesWithMultipleDefinitionsAreGeneratedAsUnions(t *testing.T) {
 	root := &Schema{}
 	root.Title = "Multiple possible types"
 	root.Properties = map[string]*Schema{
@@ -617,13 +621,23 @@ func TestThatTypesWithMultipleDefinitionsAreGeneratedAsEmptyInterfaces(t *testin
 
 	if o, ok := results["MultiplePossibleTypes"]; ok {
 		if f, ok := o.Fields["Name"]; ok {
-			if f.Type != "interface{}" {
-				t.Errorf("Since the schema has multiple types for the item, the property type should be []interface{}, but was %s.", f.Type)
+			if f.Type != "NameType" {
+				t.Errorf("Since the schema has multiple types for the item, the property type should be NameType, but was %s.", f.Type)
 			}
 		} else {
 			t.Errorf("Expected the MultiplePossibleTypes type to have a Name field, but none was found.")
 		}
 	}
+
+	expected := []OneOfType{
+		{ShortType: "String", Type: "string", JSONType: "string"},
+		{ShortType: "Int", Type: "int", JSONType: "integer"},
+	}
+	if oneOf, ok := g.OneOfs["NameType"]; !ok {
+		t.Errorf("The NameType union should have been made")
+	} else if !reflect.DeepEqual(oneOf.Types, expected) {
+		t.Errorf("Expected types %v, got %v", expected, oneOf.Types)
+	}
 }
 
 func TestThatUnmarshallingIsPossible(t *testing.T) {
@@ -737,7 +751,7 @@ func TestTypeAliases(t *testing.T) {
 			aliases: 1,
 		},
 		{
-			gotype: "map[string]interface{}",
+			gotype: "map[string]Anonymous1Type",
 			input: &Schema{
 				TypeValue:            "object",
 				AdditionalProperties: (*AdditionalProperties)(&Schema{TypeValue: []interface{}{"string", "integer"}}),
@@ -779,3 +793,845 @@ func TestTypeAliases(t *testing.T) {
 type Root struct {
 	Name interface{} `json:"name,omitempty"`
 }
+
+func TestAllOfGeneration(t *testing.T) {
+	root := &Schema{
+		Title: "Extended",
+		AllOf: []*Schema{
+			{Reference: "#/definitions/base"},
+			{
+				Properties: map[string]*Schema{
+					"extra": {TypeValue: "integer"},
+					"name":  {Description: "refined name"},
+				},
+				Required: []string{"extra"},
+			},
+		},
+		Definitions: map[string]*Schema{
+			"base": {
+				TypeValue: "object",
+				Properties: map[string]*Schema{
+					"name": {TypeValue: "string"},
+				},
+				Required:             []string{"name"},
+				AdditionalProperties: &AdditionalProperties{TypeValue: "string"},
+			},
+		},
+	}
+	root.Init()
+
+	g := New(root)
+	if err := g.CreateTypes(); err != nil {
+		t.Fatal("Failed to create structs: ", err)
+	}
+
+	strct, ok := g.Structs["Extended"]
+	if !ok {
+		t.Fatalf("Extended struct was not generated, got %v", getStructNamesFromMap(g.Structs))
+	}
+	if len(strct.Fields) != 3 {
+		t.Errorf("Expected 3 fields, got %d", len(strct.Fields))
+	}
+	testField(strct.Fields["Name"], "name", "Name", "string", true, t)
+	testField(strct.Fields["Extra"], "extra", "Extra", "int", true, t)
+	testField(strct.Fields["AdditionalProperties"], "-", "AdditionalProperties", "map[string]string", false, t)
+	if _, ok := g.Aliases["Extended"]; ok {
+		t.Error("Extended should not be an alias")
+	}
+}
+
+func TestAllOfIncompatiblePropertyTypes(t *testing.T) {
+	root := &Schema{
+		Title: "Conflict",
+		AllOf: []*Schema{
+			{Properties: map[string]*Schema{"name": {TypeValue: "string"}}},
+			{Properties: map[string]*Schema{"name": {TypeValue: "integer"}}},
+		},
+	}
+	root.Init()
+
+	g := New(root)
+	err := g.CreateTypes()
+	if err == nil {
+		t.Fatal("Expected an error for incompatible property types")
+	}
+	if !strings.Contains(err.Error(), "\"name\"") {
+		t.Errorf("Expected the error to name the property, got %q", err)
+	}
+}
+
+func TestAllOfPrimitive(t *testing.T) {
+	root := &Schema{
+		Title: "Code",
+		AllOf: []*Schema{
+			{TypeValue: "string"},
+			{Description: "some constraint"},
+		},
+	}
+	root.Init()
+
+	g := New(root)
+	if err := g.CreateTypes(); err != nil {
+		t.Fatal(err)
+	}
+	if g.Aliases["Code"].Type != "string" {
+		t.Errorf("Expected Code to be a string alias, got %q", g.Aliases["Code"].Type)
+	}
+}
+
+func TestAnyOfGeneration(t *testing.T) {
+	root := &Schema{
+		Title:     "Event",
+		TypeValue: "object",
+		Properties: map[string]*Schema{
+			"payload": {
+				AnyOf: []*Schema{
+					{Reference: "#/definitions/click"},
+					{TypeValue: "string"},
+				},
+			},
+			"label": {
+				AnyOf: []*Schema{
+					{TypeValue: "string"},
+					{TypeValue: "null"},
+				},
+			},
+		},
+		Definitions: map[string]*Schema{
+			"click": {TypeValue: "object", Properties: map[string]*Schema{"x": {TypeValue: "integer"}}},
+		},
+	}
+	root.Init()
+
+	g := New(root)
+	if err := g.CreateTypes(); err != nil {
+		t.Fatal(err)
+	}
+
+	testField(g.Structs["Event"].Fields["Payload"], "payload", "Payload", "PayloadType", false, t)
+	testField(g.Structs["Event"].Fields["Label"], "label", "Label", "OneOfStringNull", false, t)
+
+	anyOf, ok := g.AnyOfs["PayloadType"]
+	if !ok {
+		t.Fatal("PayloadType was not generated")
+	}
+	expected := []OneOfType{
+		{ShortType: "Click", Type: "*Click", JSONType: "object"},
+		{ShortType: "String", Type: "string", JSONType: "string"},
+	}
+	if !reflect.DeepEqual(anyOf.Types, expected) {
+		t.Errorf("Expected types %v, got %v", expected, anyOf.Types)
+	}
+}
+
+func TestUnionOfTheSameTypes(t *testing.T) {
+	maxLength := 8
+	root := &Schema{
+		Title:     "Event",
+		TypeValue: "object",
+		Properties: map[string]*Schema{
+			"payload": {
+				AnyOf: []*Schema{
+					{TypeValue: "string"},
+					{TypeValue: "string", MaxLength: &maxLength},
+					{TypeValue: "integer"},
+				},
+			},
+		},
+	}
+	root.Init()
+
+	g := New(root)
+	if err := g.CreateTypes(); err != nil {
+		t.Fatal(err)
+	}
+	expected := []OneOfType{
+		{ShortType: "String", Type: "string", JSONType: "string"},
+		{ShortType: "Int", Type: "int", JSONType: "integer"},
+	}
+	if types := g.AnyOfs["PayloadType"].Types; !reflect.DeepEqual(types, expected) {
+		t.Errorf("Expected types %v, got %v", expected, types)
+	}
+
+	root = &Schema{
+		Title: "Value",
+		OneOf: []*Schema{
+			{TypeValue: "string", Title: "name"},
+			{TypeValue: "integer", Title: "name"},
+		},
+	}
+	root.Init()
+	if err := New(root).CreateTypes(); err == nil {
+		t.Error("Expected an error for the sub-schemas with the same name")
+	}
+}
+
+func TestUnionOfCompositeTypes(t *testing.T) {
+	root := &Schema{
+		Title:     "Event",
+		TypeValue: "object",
+		Properties: map[string]*Schema{
+			"tags": {
+				AnyOf: []*Schema{
+					{TypeValue: "array", Items: &Schema{TypeValue: "string"}},
+					{TypeValue: "object", AdditionalProperties: (*AdditionalProperties)(&Schema{TypeValue: "integer"})},
+					{TypeValue: "string"},
+				},
+			},
+		},
+	}
+	root.Init()
+
+	g := New(root)
+	if err := g.CreateTypes(); err != nil {
+		t.Fatal(err)
+	}
+	expected := []OneOfType{
+		{ShortType: "Array", Type: "[]string", JSONType: "array"},
+		{ShortType: "Object", Type: "map[string]int", JSONType: "object"},
+		{ShortType: "String", Type: "string", JSONType: "string"},
+	}
+	if types := g.AnyOfs["TagsType"].Types; !reflect.DeepEqual(types, expected) {
+		t.Errorf("Expected types %v, got %v", expected, types)
+	}
+}
+
+func TestOneOfDiscriminatorDetection(t *testing.T) {
+	kind := func(value string) *Schema {
+		return &Schema{Const: json.RawMessage(`"` + value + `"`)}
+	}
+	root := &Schema{
+		Title: "Message",
+		OneOf: []*Schema{
+			{Title: "ping", Properties: map[string]*Schema{"kind": kind("ping"), "id": {TypeValue: "integer"}}},
+			{Title: "pong", Properties: map[string]*Schema{"kind": kind("pong"), "id": {TypeValue: "integer"}}},
+			{TypeValue: "string"},
+		},
+	}
+	root.Init()
+
+	g := New(root)
+	if err := g.CreateTypes(); err != nil {
+		t.Fatal(err)
+	}
+
+	oneOf := g.OneOfs["MessageType"]
+	if oneOf.Discriminator != "kind" {
+		t.Fatalf("Expected the discriminator to be \"kind\", got %q", oneOf.Discriminator)
+	}
+	for i, expected := range [][]string{{"ping"}, {"pong"}, nil} {
+		if !reflect.DeepEqual(oneOf.Types[i].DiscriminatorValues, expected) {
+			t.Errorf("Expected %v discriminator values for %s, got %v", expected, oneOf.Types[i].Type, oneOf.Types[i].DiscriminatorValues)
+		}
+	}
+}
+
+func TestEnumGeneration(t *testing.T) {
+	raw := func(values ...string) []json.RawMessage {
+		rv := make([]json.RawMessage, len(values))
+		for i, v := range values {
+			rv[i] = json.RawMessage(v)
+		}
+		return rv
+	}
+	root := &Schema{
+		Title:     "Ticket",
+		TypeValue: "object",
+		Properties: map[string]*Schema{
+			"status":   {TypeValue: "string", Enum: raw(`"open"`, `"closed"`, `"open"`)},
+			"priority": {TypeValue: "integer", Enum: raw(`-1`, `1`)},
+			"weight":   {TypeValue: "number", Enum: raw(`1`, `1.5`)},
+			"flag":     {Enum: raw(`"yes"`, `true`, `null`)},
+			"single":   {TypeValue: "string", Enum: raw(`"only"`)},
+		},
+	}
+	root.Init()
+
+	g := New(root)
+	if err := g.CreateTypes(); err != nil {
+		t.Fatal(err)
+	}
+
+	ticket := g.Structs["Ticket"]
+	testField(ticket.Fields["Status"], "status", "Status", "Status", false, t)
+	testField(ticket.Fields["Priority"], "priority", "Priority", "Priority", false, t)
+	testField(ticket.Fields["Weight"], "weight", "Weight", "Weight", false, t)
+	testField(ticket.Fields["Flag"], "flag", "Flag", "Flag", false, t)
+	testField(ticket.Fields["Single"], "single", "Single", "string", false, t)
+
+	tests := []struct {
+		name   string
+		typ    string
+		mixed  bool
+		values []EnumValue
+	}{
+		{"Status", "string", false, []EnumValue{{"StatusOpen", `"open"`}, {"StatusClosed", `"closed"`}}},
+		{"Priority", "int", false, []EnumValue{{"PriorityMinus1", `-1`}, {"Priority1", `1`}}},
+		{"Weight", "float64", false, []EnumValue{{"Weight1", `1`}, {"Weight1Dot5", `1.5`}}},
+		{"Flag", "string", true, []EnumValue{{"FlagYes", `"\"yes\""`}, {"FlagTrue", `"true"`}, {"FlagNull", `"null"`}}},
+	}
+	for _, test := range tests {
+		enum, ok := g.Enums[test.name]
+		if !ok {
+			t.Errorf("Enum %s was not generated", test.name)
+			continue
+		}
+		if enum.Type != test.typ || enum.Mixed != test.mixed {
+			t.Errorf("Expected %s to be a %s (mixed: %v), got %s (mixed: %v)", test.name, test.typ, test.mixed, enum.Type, enum.Mixed)
+		}
+		if !reflect.DeepEqual(enum.Values, test.values) {
+			t.Errorf("Expected %s values %v, got %v", test.name, test.values, enum.Values)
+		}
+	}
+}
+
+func TestEnumNameClash(t *testing.T) {
+	root := &Schema{
+		Title:     "Order",
+		TypeValue: "object",
+		Properties: map[string]*Schema{
+			"status": {TypeValue: "string", Enum: []json.RawMessage{json.RawMessage(`"new"`), json.RawMessage(`"paid"`)}},
+			"customer": {
+				TypeValue: "object",
+				Properties: map[string]*Schema{
+					"status": {TypeValue: "string", Enum: []json.RawMessage{json.RawMessage(`"active"`), json.RawMessage(`"banned"`)}},
+				},
+			},
+		},
+	}
+	root.Init()
+
+	g := New(root)
+	if err := g.CreateTypes(); err != nil {
+		t.Fatal(err)
+	}
+	testField(g.Structs["Customer"].Fields["Status"], "status", "Status", "Status", false, t)
+	testField(g.Structs["Order"].Fields["Status"], "status", "Status", "OrderStatus", false, t)
+	expected := []EnumValue{{"OrderStatusNew", `"new"`}, {"OrderStatusPaid", `"paid"`}}
+	if values := g.Enums["OrderStatus"].Values; !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected OrderStatus values %v, got %v", expected, values)
+	}
+	expected = []EnumValue{{"StatusActive", `"active"`}, {"StatusBanned", `"banned"`}}
+	if values := g.Enums["Status"].Values; !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected Status values %v, got %v", expected, values)
+	}
+}
+
+func TestFormatTypes(t *testing.T) {
+	root := &Schema{
+		Title:     "Resource",
+		TypeValue: "object",
+		Properties: map[string]*Schema{
+			"id":      {TypeValue: "string", Format: "uuid"},
+			"created": {TypeValue: "string", Format: "date-time"},
+			"link":    {TypeValue: "string", Format: "uri"},
+			"email":   {TypeValue: "string", Format: "email"},
+			"count":   {TypeValue: "integer", Format: "uuid"},
+			"updated": {Reference: "#/definitions/timestamp"},
+			"history": {TypeValue: "array", Items: &Schema{TypeValue: "string", Format: "date-time"}},
+			"hosts": {
+				TypeValue:            "object",
+				AdditionalProperties: (*AdditionalProperties)(&Schema{TypeValue: "string", Format: "ipv4"}),
+			},
+		},
+		Definitions: map[string]*Schema{
+			"timestamp": {TypeValue: "string", Format: "date-time"},
+		},
+	}
+	root.Init()
+
+	g := New(root)
+	delete(g.Formats, "uri")
+	if err := g.CreateTypes(); err != nil {
+		t.Fatal(err)
+	}
+
+	resource := g.Structs["Resource"]
+	testField(resource.Fields["Id"], "id", "Id", "UUID", false, t)
+	testField(resource.Fields["Created"], "created", "Created", "time.Time", false, t)
+	testField(resource.Fields["Link"], "link", "Link", "string", false, t)
+	testField(resource.Fields["Email"], "email", "Email", "string", false, t)
+	testField(resource.Fields["Count"], "count", "Count", "int", false, t)
+	testField(resource.Fields["Updated"], "updated", "Updated", "time.Time", false, t)
+	testField(resource.Fields["History"], "history", "History", "[]time.Time", false, t)
+	testField(resource.Fields["Hosts"], "hosts", "Hosts", "map[string]net.IP", false, t)
+
+	if DefaultFormats["uri"] != "*url.URL" {
+		t.Error("Deleting a generator format should not change the default formats")
+	}
+}
+
+func TestURIFormatTypes(t *testing.T) {
+	root := &Schema{
+		Title:     "Resource",
+		TypeValue: "object",
+		Properties: map[string]*Schema{
+			"link":     {TypeValue: "string", Format: "uri"},
+			"home":     {Reference: "#/definitions/link"},
+			"links":    {TypeValue: "array", Items: &Schema{TypeValue: "string", Format: "uri"}},
+			"previous": {TypeValue: []interface{}{"string", "null"}, Format: "uri"},
+			"mirrors": {
+				TypeValue:            "object",
+				AdditionalProperties: (*AdditionalProperties)(&Schema{TypeValue: "string", Format: "uri"}),
+			},
+		},
+		Definitions: map[string]*Schema{
+			"link": {TypeValue: "string", Format: "uri"},
+		},
+	}
+	root.Init()
+
+	g := New(root)
+	if err := g.CreateTypes(); err != nil {
+		t.Fatal(err)
+	}
+
+	resource := g.Structs["Resource"]
+	testField(resource.Fields["Link"], "link", "Link", "*url.URL", false, t)
+	testField(resource.Fields["Home"], "home", "Home", "*url.URL", false, t)
+	testField(resource.Fields["Links"], "links", "Links", "[]string", false, t)
+	testField(resource.Fields["Previous"], "previous", "Previous", "Nullable[string]", false, t)
+	testField(resource.Fields["Mirrors"], "mirrors", "Mirrors", "map[string]string", false, t)
+}
+
+func TestIntegerTypes(t *testing.T) {
+	dec := func(v int64) *decimal.Decimal {
+		d := decimal.New(v, 0)
+		return &d
+	}
+	tests := []struct {
+		schema   *Schema
+		expected string
+	}{
+		{&Schema{TypeValue: "integer"}, "int"},
+		{&Schema{TypeValue: "integer", Format: "int32"}, "int32"},
+		{&Schema{TypeValue: "integer", Format: "int64"}, "int64"},
+		{&Schema{TypeValue: "integer", Format: "uint16"}, "uint16"},
+		{&Schema{TypeValue: "integer", Format: "int64", Minimum: dec(0)}, "int64"},
+		{&Schema{TypeValue: "integer", Format: "int32", Minimum: dec(1), Maximum: dec(10)}, "int32"},
+		{&Schema{TypeValue: "integer", Format: "uint8", Minimum: dec(0)}, "uint8"},
+		{&Schema{TypeValue: "integer", Minimum: dec(0)}, "uint"},
+		{&Schema{TypeValue: "integer", Minimum: dec(-1), Maximum: dec(100)}, "int"},
+		{&Schema{TypeValue: "integer", Maximum: dec(math.MaxInt32 + 1)}, "int64"},
+		{&Schema{TypeValue: "integer", Minimum: dec(math.MinInt32 - 1)}, "int64"},
+		{&Schema{TypeValue: "integer", Minimum: dec(1), Maximum: dec(math.MaxUint32)}, "uint"},
+		{&Schema{TypeValue: "integer", Minimum: dec(1), Maximum: dec(math.MaxUint32 + 1)}, "uint64"},
+	}
+
+	for _, test := range tests {
+		if actual := getIntegerTypeName(test.schema); actual != test.expected {
+			t.Errorf("Expected %s for format %q, minimum %v and maximum %v, got %s",
+				test.expected, test.schema.Format, test.schema.Minimum, test.schema.Maximum, actual)
+		}
+	}
+}
+
+func TestNumberTypes(t *testing.T) {
+	g := New()
+	if typ := g.getNumberTypeName(&Schema{TypeValue: "number"}); typ != "float64" {
+		t.Errorf("Expected float64, got %s", typ)
+	}
+	if typ := g.getNumberTypeName(&Schema{TypeValue: "number", Format: "float"}); typ != "float32" {
+		t.Errorf("Expected float32, got %s", typ)
+	}
+	g.NumberType = "json.Number"
+	if typ := g.getNumberTypeName(&Schema{TypeValue: "number"}); typ != "json.Number" {
+		t.Errorf("Expected json.Number, got %s", typ)
+	}
+	if typ := g.getNumberTypeName(&Schema{TypeValue: "number", Format: "double"}); typ != "float64" {
+		t.Errorf("Expected float64, got %s", typ)
+	}
+}
+
+func TestValidationLimits(t *testing.T) {
+	var schema Schema
+	if err := json.Unmarshal([]byte(`{"type": "integer", "minimum": 1, "exclusiveMinimum": 3, "maximum": 10, "exclusiveMaximum": true}`), &schema); err != nil {
+		t.Fatal(err)
+	}
+	v := newValidation(&schema)
+	if v == nil {
+		t.Fatal("Expected a validation")
+	}
+	if v.Minimum.String() != "3" || !v.ExclusiveMinimum {
+		t.Errorf("Expected an exclusive minimum of 3, got %s (exclusive: %v)", v.Minimum, v.ExclusiveMinimum)
+	}
+	if v.Maximum.String() != "10" || !v.ExclusiveMaximum {
+		t.Errorf("Expected an exclusive maximum of 10, got %s (exclusive: %v)", v.Maximum, v.ExclusiveMaximum)
+	}
+
+	if v := newValidation(&Schema{TypeValue: "string", Pattern: "(?=lookahead)"}); v != nil {
+		t.Errorf("Expected unsupported patterns to be ignored, got %+v", v)
+	}
+
+	d := &OutputData{EmptyTypes: map[string]string{}}
+	checks := d.ValidationChecks(Field{Name: "Count", Type: "int", Validation: v})
+	if len(checks) != 2 {
+		t.Fatalf("Expected 2 checks, got %+v", checks)
+	}
+	if checks[0].Cond != "!IsEmpty(s.Count) && int64(s.Count) <= 3" {
+		t.Errorf("Unexpected check %q", checks[0].Cond)
+	}
+}
+
+func TestDefsAndAnchors(t *testing.T) {
+	root, err := Parse(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "https://example.com/person.json",
+		"title": "Person",
+		"type": "object",
+		"properties": {
+			"address": {"$ref": "#/$defs/address"},
+			"country": {"$ref": "#country"}
+		},
+		"$defs": {
+			"address": {
+				"type": "object",
+				"properties": {
+					"street": {"type": "string"}
+				}
+			},
+			"country": {
+				"$anchor": "country",
+				"type": "object",
+				"properties": {
+					"code": {"type": "string"}
+				}
+			}
+		}
+	}`, &url.URL{Scheme: "file", Path: "person.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := New(root)
+	if err := g.CreateTypes(); err != nil {
+		t.Fatal("Failed to create structs: ", err)
+	}
+
+	strct, ok := g.Structs["Person"]
+	if !ok {
+		t.Fatalf("Person struct was not generated, got %v", getStructNamesFromMap(g.Structs))
+	}
+	testField(strct.Fields["Address"], "address", "Address", "*Address", false, t)
+	testField(strct.Fields["Country"], "country", "Country", "*Country", false, t)
+	if _, ok := g.Structs["Address"]; !ok {
+		t.Errorf("Address struct was not generated, got %v", getStructNamesFromMap(g.Structs))
+	}
+}
+
+func TestBrokenDefs(t *testing.T) {
+	root := &Schema{
+		Title:     "Person",
+		TypeValue: "object",
+		Properties: map[string]*Schema{
+			"name": {TypeValue: "string"},
+		},
+		Defs: map[string]*Schema{
+			"address": {Reference: "#/$defs/missing"},
+		},
+	}
+	root.Init()
+
+	if err := New(root).CreateTypes(); err == nil {
+		t.Error("Expected an error for the broken $defs entry")
+	}
+}
+
+func TestOpenAPIComponents(t *testing.T) {
+	doc := `
+openapi: 3.1.0
+info:
+  title: Shop
+  version: 1.0.0
+components:
+  schemas:
+    Order:
+      type: object
+      properties:
+        quantity:
+          type: integer
+          nullable: true
+        item:
+          $ref: '#/components/schemas/Item'
+    Item:
+      type: object
+      properties:
+        name:
+          type: string
+`
+	root, err := parseInput([]byte(doc), "shop.yaml", &url.URL{Scheme: "file", Path: "/shop.yaml"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := New(root)
+	if err := g.CreateTypes(); err != nil {
+		t.Fatal("Failed to create structs: ", err)
+	}
+	if len(g.Aliases) != 0 {
+		t.Errorf("Expected no alias for the OpenAPI document, got %v", g.Aliases)
+	}
+	strct, ok := g.Structs["Order"]
+	if !ok {
+		t.Fatalf("Order struct was not generated, got %v", getStructNamesFromMap(g.Structs))
+	}
+	testField(strct.Fields["Quantity"], "quantity", "Quantity", "Nullable[int]", false, t)
+	testField(strct.Fields["Item"], "item", "Item", "*Item", false, t)
+}
+
+func TestNullableTypes(t *testing.T) {
+	root := &Schema{
+		Title:     "Profile",
+		TypeValue: "object",
+		Properties: map[string]*Schema{
+			"age":      {TypeValue: []interface{}{"integer", "null"}},
+			"nickname": {TypeValue: []interface{}{"null", "string"}},
+			"created":  {TypeValue: []interface{}{"string", "null"}, Format: "date-time"},
+			"tags":     {TypeValue: "array", Nullable: true, Items: &Schema{TypeValue: "string"}},
+			"address": {
+				TypeValue:  []interface{}{"object", "null"},
+				Properties: map[string]*Schema{"city": {TypeValue: "string"}},
+			},
+			"any": {TypeValue: []interface{}{"string", "integer", "null"}},
+			"color": {
+				TypeValue: []interface{}{"string", "null"},
+				Enum:      []json.RawMessage{json.RawMessage(`"red"`), json.RawMessage(`null`), json.RawMessage(`"blue"`)},
+			},
+		},
+	}
+	root.Init()
+
+	g := New(root)
+	if err := g.CreateTypes(); err != nil {
+		t.Fatal(err)
+	}
+	strct := g.Structs["Profile"]
+	testField(strct.Fields["Age"], "age", "Age", "Nullable[int]", false, t)
+	testField(strct.Fields["Nickname"], "nickname", "Nickname", "Nullable[string]", false, t)
+	testField(strct.Fields["Created"], "created", "Created", "Nullable[time.Time]", false, t)
+	testField(strct.Fields["Tags"], "tags", "Tags", "Nullable[[]string]", false, t)
+	testField(strct.Fields["Address"], "address", "Address", "Nullable[Address]", false, t)
+	testField(strct.Fields["Any"], "any", "Any", "AnyType", false, t)
+	testField(strct.Fields["Color"], "color", "Color", "Nullable[Color]", false, t)
+	color := g.Enums["Color"]
+	expected := []EnumValue{{"ColorRed", `"red"`}, {"ColorBlue", `"blue"`}}
+	if color.Type != "string" || color.Mixed || !reflect.DeepEqual(color.Values, expected) {
+		t.Errorf("Expected the Color string enum values %v, got %v (mixed: %v)", expected, color.Values, color.Mixed)
+	}
+	if len(root.Properties["color"].Enum) != 3 {
+		t.Error("Expected the null value to be kept in the schema")
+	}
+	if !strct.GenerateCode {
+		t.Error("Expected the marshalling code of the nullable fields to be generated")
+	}
+	if _, ok := g.Structs["Address"]; !ok {
+		t.Errorf("Address struct was not generated, got %v", getStructNamesFromMap(g.Structs))
+	}
+}
+
+func TestPatternProperties(t *testing.T) {
+	root := &Schema{
+		Title:     "Catalog",
+		TypeValue: "object",
+		Properties: map[string]*Schema{
+			"labels": {
+				TypeValue:         "object",
+				PatternProperties: map[string]*Schema{"^[a-z]{2}$": {TypeValue: "string"}},
+			},
+			"metrics": {
+				TypeValue:  "object",
+				Properties: map[string]*Schema{"total": {TypeValue: "integer"}},
+				PatternProperties: map[string]*Schema{
+					"^rate_":  {TypeValue: "number"},
+					"^count_": {TypeValue: "integer"},
+				},
+			},
+		},
+	}
+	root.Init()
+
+	g := New(root)
+	if err := g.CreateTypes(); err != nil {
+		t.Fatal(err)
+	}
+	testField(g.Structs["Catalog"].Fields["Labels"], "labels", "Labels", "map[string]string", false, t)
+	metrics, ok := g.Structs["Metrics"]
+	if !ok {
+		t.Fatalf("Metrics struct was not generated, got %v", getStructNamesFromMap(g.Structs))
+	}
+	testField(metrics.Fields["PatternProperties1"], "-", "PatternProperties1", "map[string]int", false, t)
+	testField(metrics.Fields["PatternProperties2"], "-", "PatternProperties2", "map[string]float64", false, t)
+	expected := []PatternProperty{
+		{Pattern: "^count_", Name: "PatternProperties1", Type: "int"},
+		{Pattern: "^rate_", Name: "PatternProperties2", Type: "float64"},
+	}
+	if !reflect.DeepEqual(metrics.PatternProperties, expected) {
+		t.Errorf("Expected pattern properties %v, got %v", expected, metrics.PatternProperties)
+	}
+
+	root = &Schema{
+		Title:             "Invalid",
+		TypeValue:         "object",
+		PatternProperties: map[string]*Schema{"(": {TypeValue: "string"}},
+	}
+	root.Init()
+	if err := New(root).CreateTypes(); err == nil {
+		t.Error("Expected an error for an invalid patternProperties regular expression")
+	}
+}
+
+func TestTuples(t *testing.T) {
+	minItems := 2
+	noMore := false
+	root := &Schema{
+		Title:     "Route",
+		TypeValue: "object",
+		Properties: map[string]*Schema{
+			"point": {
+				TypeValue: "array",
+				PrefixItems: []*Schema{
+					{TypeValue: "number", Title: "longitude"},
+					{TypeValue: "number", Title: "latitude"},
+					{TypeValue: "string"},
+				},
+				MinItems:            &minItems,
+				AdditionalItemsBool: &noMore,
+			},
+			"row": {
+				TypeValue:       "array",
+				PrefixItems:     []*Schema{{TypeValue: "string"}},
+				AdditionalItems: &Schema{TypeValue: "integer"},
+			},
+		},
+	}
+	root.Init()
+
+	g := New(root)
+	if err := g.CreateTypes(); err != nil {
+		t.Fatal(err)
+	}
+	testField(g.Structs["Route"].Fields["Point"], "point", "Point", "*Point", false, t)
+	point, ok := g.Structs["Point"]
+	if !ok {
+		t.Fatalf("Point struct was not generated, got %v", getStructNamesFromMap(g.Structs))
+	}
+	testField(point.Fields["Longitude"], "0", "Longitude", "float64", true, t)
+	testField(point.Fields["Latitude"], "1", "Latitude", "float64", true, t)
+	testField(point.Fields["Item2"], "2", "Item2", "string", false, t)
+	if expected := []string{"Longitude", "Latitude", "Item2"}; !reflect.DeepEqual(point.TupleFields, expected) {
+		t.Errorf("Expected the tuple fields %v, got %v", expected, point.TupleFields)
+	}
+	if point.AdditionalItems != "false" || point.TupleMinItems() != 2 {
+		t.Errorf("Expected no additional items and 2 items at least, got %q and %d", point.AdditionalItems, point.TupleMinItems())
+	}
+
+	row := g.Structs["Row"]
+	testField(row.Fields["AdditionalItems"], "-", "AdditionalItems", "[]int", false, t)
+	if row.AdditionalItems != "int" {
+		t.Errorf("Expected int additional items, got %q", row.AdditionalItems)
+	}
+}
+
+func TestConditionalSchemas(t *testing.T) {
+	root := &Schema{
+		Title:     "Server",
+		TypeValue: "object",
+		Properties: map[string]*Schema{
+			"mode":    {TypeValue: "string"},
+			"billing": {TypeValue: "string"},
+		},
+		If: &Schema{
+			Properties: map[string]*Schema{"mode": {Const: json.RawMessage(`"tls"`)}},
+			Required:   []string{"mode"},
+		},
+		Then: &Schema{
+			Properties: map[string]*Schema{"cert": {TypeValue: "string"}},
+			Required:   []string{"cert"},
+		},
+		DependentRequired: map[string][]string{"billing": {"address"}},
+		DependentSchemas: map[string]*Schema{
+			"mode": {
+				Properties: map[string]*Schema{"port": {TypeValue: "integer"}},
+			},
+		},
+	}
+	root.Init()
+
+	g := New(root)
+	if err := g.CreateTypes(); err != nil {
+		t.Fatal(err)
+	}
+	server := g.Structs["Server"]
+	testField(server.Fields["Cert"], "cert", "Cert", "string", false, t)
+	testField(server.Fields["Port"], "port", "Port", "int", false, t)
+
+	expected := []Condition{
+		{Present: []string{"mode"}, Values: map[string][]string{"mode": {`"tls"`}}, Required: []string{"cert"}},
+		{Present: []string{"billing"}, Required: []string{"address"}},
+	}
+	if !reflect.DeepEqual(server.Conditions, expected) {
+		t.Errorf("Expected the conditions %v, got %v", expected, server.Conditions)
+	}
+}
+
+func TestInitialisms(t *testing.T) {
+	g := New()
+	g.Initialisms = map[string]bool{"ID": true, "URL": true, "HTML": true, "SKU": true}
+	tests := map[string]string{
+		"user_id":    "UserID",
+		"html_url":   "HTMLURL",
+		"productSku": "ProductSKU",
+		"userIDs":    "UserIDs",
+		"identity":   "Identity",
+		"2fa_id":     "_2faID",
+		"__type__":   "DataType",
+	}
+	for input, expected := range tests {
+		if actual := g.golangName(input); actual != expected {
+			t.Errorf("For input '%s' expected '%s' but got '%s'.", input, expected, actual)
+		}
+	}
+	if actual := New().golangName("user_id"); actual != "UserId" {
+		t.Errorf("Expected no initialisms by default, got '%s'.", actual)
+	}
+}
+
+func TestGoNames(t *testing.T) {
+	root := &Schema{
+		Title:     "account",
+		GoName:    "Customer",
+		TypeValue: "object",
+		Properties: map[string]*Schema{
+			"user_id": {TypeValue: "string", GoName: "UID"},
+			"home":    {Reference: "#/definitions/address"},
+		},
+		Definitions: map[string]*Schema{
+			"address": {
+				TypeValue:  "object",
+				GoName:     "PostalAddress",
+				Properties: map[string]*Schema{"city": {TypeValue: "string"}},
+			},
+		},
+	}
+	root.Init()
+
+	g := New(root)
+	if err := g.CreateTypes(); err != nil {
+		t.Fatal("Failed to create structs: ", err)
+	}
+	strct, ok := g.Structs["Customer"]
+	if !ok {
+		t.Fatalf("Customer struct was not generated, got %v", getStructNamesFromMap(g.Structs))
+	}
+	testField(strct.Fields["UID"], "user_id", "UID", "string", false, t)
+	testField(strct.Fields["Home"], "home", "Home", "*PostalAddress", false, t)
+
+	root = &Schema{
+		TypeValue:  "object",
+		Properties: map[string]*Schema{"id": {TypeValue: "object", GoName: "not-a-name"}},
+	}
+	root.Init()
+	if err := New(root).CreateTypes(); err == nil || !strings.Contains(err.Error(), "x-go-name") {
+		t.Errorf("Expected an error for an invalid x-go-name, got %v", err)
+	}
+}
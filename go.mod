@@ -2,4 +2,7 @@ module github.com/orus-io/json-schema-generate
 
 go 1.13
 
-require github.com/shopspring/decimal v0.0.0-20191130220710-360f2bc03045
+require (
+	github.com/shopspring/decimal v0.0.0-20191130220710-360f2bc03045
+	gopkg.in/yaml.v3 v3.0.1
+)
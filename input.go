@@ -14,44 +14,80 @@ import (
 func ReadInputFiles(inputFiles []string, schemaKeyRequired bool) ([]*Schema, error) {
 	schemas := make([]*Schema, len(inputFiles))
 	for i, file := range inputFiles {
-		b, err := ioutil.ReadFile(file)
+		var err error
+		schemas[i], err = readInputFile(file, schemaKeyRequired)
 		if err != nil {
-			return nil, errors.New("failed to read the input file with error " + err.Error())
+			return nil, err
 		}
+	}
 
-		abPath, err := abs(file)
-		if err != nil {
-			return nil, errors.New("failed to normalise input path with error " + err.Error())
-		}
+	return schemas, nil
+}
 
-		fileURI := url.URL{
-			Scheme: "file",
-			Path:   abPath,
-		}
+// readInputFile reads a single JSON schema file from disk.
+func readInputFile(file string, schemaKeyRequired bool) (*Schema, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.New("failed to read the input file with error " + err.Error())
+	}
+
+	abPath, err := abs(file)
+	if err != nil {
+		return nil, errors.New("failed to normalise input path with error " + err.Error())
+	}
+
+	fileURI := url.URL{
+		Scheme: "file",
+		Path:   abPath,
+	}
 
-		schemas[i], err = ParseWithSchemaKeyRequired(string(b), &fileURI, schemaKeyRequired)
+	return parseInput(b, file, &fileURI, schemaKeyRequired)
+}
+
+// parseInput parses a JSON or YAML schema document, reporting the position of
+// the syntax errors in file.
+func parseInput(b []byte, file string, uri *url.URL, schemaKeyRequired bool) (*Schema, error) {
+	position := func(offset int) (int, int, error) {
+		return lineAndCharacter(b, offset)
+	}
+	if isYAML(file, b) {
+		var positions []yamlPosition
+		var err error
+		b, positions, err = yamlToJSON(b)
 		if err != nil {
-			if jsonError, ok := err.(*json.SyntaxError); ok {
-				line, character, lcErr := lineAndCharacter(b, int(jsonError.Offset))
-				errStr := fmt.Sprintf("cannot parse JSON schema due to a syntax error at %s line %d, character %d: %v\n", file, line, character, jsonError.Error())
-				if lcErr != nil {
-					errStr += fmt.Sprintf("couldn't find the line and character position of the error due to error %v\n", lcErr)
-				}
-				return nil, errors.New(errStr)
+			return nil, fmt.Errorf("cannot parse YAML schema %s: %v", file, err)
+		}
+		position = func(offset int) (int, int, error) {
+			return yamlLineAndColumn(positions, offset)
+		}
+	}
+	var schema *Schema
+	var err error
+	if isOpenAPI(b) {
+		schema, err = ParseOpenAPI(string(b), uri)
+	} else {
+		schema, err = ParseWithSchemaKeyRequired(string(b), uri, schemaKeyRequired)
+	}
+	if err != nil {
+		if jsonError, ok := err.(*json.SyntaxError); ok {
+			line, character, lcErr := position(int(jsonError.Offset))
+			errStr := fmt.Sprintf("cannot parse JSON schema due to a syntax error at %s line %d, character %d: %v\n", file, line, character, jsonError.Error())
+			if lcErr != nil {
+				errStr += fmt.Sprintf("couldn't find the line and character position of the error due to error %v\n", lcErr)
 			}
-			if jsonError, ok := err.(*json.UnmarshalTypeError); ok {
-				line, character, lcErr := lineAndCharacter(b, int(jsonError.Offset))
-				errStr := fmt.Sprintf("the JSON type '%v' cannot be converted into the Go '%v' type on struct '%s', field '%v'. See input file %s line %d, character %d\n", jsonError.Value, jsonError.Type.Name(), jsonError.Struct, jsonError.Field, file, line, character)
-				if lcErr != nil {
-					errStr += fmt.Sprintf("couldn't find the line and character position of the error due to error %v\n", lcErr)
-				}
-				return nil, errors.New(errStr)
+			return nil, errors.New(errStr)
+		}
+		if jsonError, ok := err.(*json.UnmarshalTypeError); ok {
+			line, character, lcErr := position(int(jsonError.Offset))
+			errStr := fmt.Sprintf("the JSON type '%v' cannot be converted into the Go '%v' type on struct '%s', field '%v'. See input file %s line %d, character %d\n", jsonError.Value, jsonError.Type.Name(), jsonError.Struct, jsonError.Field, file, line, character)
+			if lcErr != nil {
+				errStr += fmt.Sprintf("couldn't find the line and character position of the error due to error %v\n", lcErr)
 			}
-			return nil, fmt.Errorf("failed to parse the input JSON schema file %s with error %v", file, err)
+			return nil, errors.New(errStr)
 		}
+		return nil, fmt.Errorf("failed to parse the input JSON schema file %s with error %v", file, err)
 	}
-
-	return schemas, nil
+	return schema, nil
 }
 
 func lineAndCharacter(bytes []byte, offset int) (line int, character int, err error) {
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"net/url"
+	"sort"
 	"strconv"
 
 	"github.com/shopspring/decimal"
@@ -23,6 +24,10 @@ type Schema struct {
 	ID04 string `json:"id"`  // up to draft-04
 	ID06 string `json:"$id"` // from draft-06 onwards
 
+	// Anchor is a plain name fragment identifying the schema, e.g. "#foo".
+	// https://json-schema.org/draft/2020-12/json-schema-core.html#rfc.section.8.2.2
+	Anchor string `json:"$anchor"`
+
 	// Title and Description state the intent of the schema.
 	Title       string
 	Description string
@@ -33,13 +38,60 @@ type Schema struct {
 
 	Enum []json.RawMessage
 
+	// Const restricts the instance to a single value.
+	// http://json-schema.org/draft-07/json-schema-validation.html#rfc.section.6.1.3
+	Const json.RawMessage
+
+	// Format is a semantic identification of a string value, e.g. "date-time"
+	// http://json-schema.org/draft-07/json-schema-validation.html#rfc.section.7
+	Format string
+
 	// MultipleOf is the schema 'multipleOf' attribute
 	MultipleOf decimal.Decimal
 
+	// Minimum and Maximum are the inclusive bounds of a numeric instance.
+	// http://json-schema.org/draft-07/json-schema-validation.html#rfc.section.6.2.2
+	Minimum *decimal.Decimal
+	Maximum *decimal.Decimal
+
+	// ExclusiveMinimum and ExclusiveMaximum are the exclusive bounds of a
+	// numeric instance.
+	// http://json-schema.org/draft-07/json-schema-validation.html#rfc.section.6.2.3
+	ExclusiveMinimum *ExclusiveLimit
+	ExclusiveMaximum *ExclusiveLimit
+
+	// MinLength, MaxLength and Pattern restrict a string instance.
+	// http://json-schema.org/draft-07/json-schema-validation.html#rfc.section.6.3
+	MinLength *int
+	MaxLength *int
+	Pattern   string
+
+	// MinItems, MaxItems and UniqueItems restrict an array instance.
+	// http://json-schema.org/draft-07/json-schema-validation.html#rfc.section.6.4
+	MinItems    *int
+	MaxItems    *int
+	UniqueItems bool
+
+	// MinProperties and MaxProperties restrict the number of properties of an
+	// object instance.
+	// http://json-schema.org/draft-07/json-schema-validation.html#rfc.section.6.5
+	MinProperties *int
+	MaxProperties *int
+
 	// Definitions are inline re-usable schemas.
 	// http://json-schema.org/draft-07/json-schema-validation.html#rfc.section.9
 	Definitions map[string]*Schema
 
+	// Defs are inline re-usable schemas, replacing Definitions from draft 2019-09.
+	// https://json-schema.org/draft/2020-12/json-schema-core.html#rfc.section.8.2.4
+	Defs map[string]*Schema `json:"$defs"`
+
+	// OpenAPI is the version of the OpenAPI document the schema was extracted
+	// from, ComponentSchemas are the document "components/schemas".
+	// https://spec.openapis.org/oas/v3.0.3#components-object
+	OpenAPI          string             `json:"-"`
+	ComponentSchemas map[string]*Schema `json:"-"`
+
 	// Properties, Required and AdditionalProperties describe an object's child instances.
 	// http://json-schema.org/draft-07/json-schema-validation.html#rfc.section.6.5
 	Properties map[string]*Schema
@@ -51,10 +103,56 @@ type Schema struct {
 	// "additionalProperties": false
 	AdditionalPropertiesBool *bool `json:"-"`
 
+	// PatternProperties are the schemas of the properties whose name matches
+	// a regular expression.
+	// http://json-schema.org/draft-07/json-schema-validation.html#rfc.section.6.5.5
+	PatternProperties map[string]*Schema
+
 	AnyOf []*Schema
 	AllOf []*Schema
 	OneOf []*Schema
 
+	// If, Then and Else apply Then to the instances valid against If, and
+	// Else to the other ones.
+	// http://json-schema.org/draft-07/json-schema-validation.html#rfc.section.6.6
+	If   *Schema
+	Then *Schema
+	Else *Schema
+
+	// DependentRequired are the properties required when a property is
+	// present, DependentSchemas the schemas applying to the instance then.
+	// https://json-schema.org/draft/2020-12/json-schema-validation.html#rfc.section.6.5.4
+	// https://json-schema.org/draft/2020-12/json-schema-core.html#rfc.section.10.2.2.4
+	DependentRequired map[string][]string
+	DependentSchemas  map[string]*Schema
+
+	// Dependencies is the draft-07 keyword of both DependentRequired and
+	// DependentSchemas, moved to them by Init.
+	// http://json-schema.org/draft-07/json-schema-validation.html#rfc.section.6.5.7
+	Dependencies map[string]*DependencyKeyword
+
+	// Discriminator tells which oneOf sub-schema an instance matches.
+	// https://spec.openapis.org/oas/v3.0.3#discriminator-object
+	Discriminator *Discriminator
+
+	// Nullable allows a null value in addition to the type (OpenAPI 3.0).
+	// https://spec.openapis.org/oas/v3.0.3#fixed-fields-19
+	Nullable bool
+
+	// ReadOnly and WriteOnly tell the instance is only sent in responses or
+	// in requests.
+	// http://json-schema.org/draft-07/json-schema-validation.html#rfc.section.10.3
+	ReadOnly  bool
+	WriteOnly bool
+
+	// GoName forces the golang name of the type of the schema, or of the
+	// field of the property (extension).
+	GoName string `json:"x-go-name"`
+
+	// GoTags are struct tags added to the field of the property, e.g.
+	// `gorm:"primaryKey"` (extension).
+	GoTags string `json:"x-go-tags"`
+
 	// Default can be used to supply a default JSON value associated with a particular schema.
 	// http://json-schema.org/draft-07/json-schema-validation.html#rfc.section.10.2
 	Default interface{}
@@ -69,7 +167,32 @@ type Schema struct {
 
 	// Items represents the types that are permitted in the array.
 	// http://json-schema.org/draft-07/json-schema-validation.html#rfc.section.6.4
-	Items *Schema
+	Items *Schema `json:"-"`
+
+	// PrefixItems are the schemas of the items of a tuple, by position: the
+	// "items" array up to draft 2019-09, or "prefixItems" from draft 2020-12.
+	// https://json-schema.org/draft/2020-12/json-schema-core.html#rfc.section.10.3.1.1
+	PrefixItems []*Schema `json:"prefixItems"`
+
+	// AdditionalItems is the schema of the items after the PrefixItems:
+	// "additionalItems" up to draft 2019-09, or "items" from draft 2020-12.
+	AdditionalItems *Schema `json:"-"`
+
+	// "additionalItems": false
+	AdditionalItemsBool *bool `json:"-"`
+
+	// RawItems and RawAdditionalItems are the "items" and "additionalItems"
+	// keywords as parsed, moved to Items, PrefixItems and AdditionalItems by
+	// Init.
+	RawItems           *ItemsKeyword `json:"items"`
+	RawAdditionalItems *ItemsKeyword `json:"additionalItems"`
+
+	// prefixItemsKeyword is set when the tuple uses the draft 2020-12 keywords
+	prefixItemsKeyword bool
+
+	// dependenciesKeyword is set when the DependentSchemas come from the
+	// draft-07 "dependencies" keyword
+	dependenciesKeyword bool
 
 	// NameCount is the number of times the instance name was encountered across the schema.
 	NameCount int `json:"-" `
@@ -87,6 +210,37 @@ type Schema struct {
 	GeneratedType string `json:"-"`
 }
 
+// Discriminator is an OpenAPI discriminator object.
+type Discriminator struct {
+	// PropertyName is the name of the property holding the discriminator value
+	PropertyName string `json:"propertyName"`
+	// Mapping maps the discriminator values to schema names or references
+	Mapping map[string]string `json:"mapping"`
+}
+
+// ExclusiveLimit is an exclusiveMinimum or exclusiveMaximum keyword. Up to
+// draft-04 it is a boolean making minimum or maximum exclusive, from draft-06
+// onwards it is the exclusive limit itself.
+type ExclusiveLimit struct {
+	// Exclusive is the draft-04 boolean value
+	Exclusive bool
+	// Limit is the draft-06 numeric value
+	Limit *decimal.Decimal
+}
+
+// UnmarshalJSON handles unmarshalling an ExclusiveLimit from JSON.
+func (l *ExclusiveLimit) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &l.Exclusive); err == nil {
+		return nil
+	}
+	var d decimal.Decimal
+	if err := json.Unmarshal(data, &d); err != nil {
+		return err
+	}
+	l.Limit = &d
+	return nil
+}
+
 // UnmarshalJSON handles unmarshalling AdditionalProperties from JSON.
 func (ap *AdditionalProperties) UnmarshalJSON(data []byte) error {
 	var b bool
@@ -119,6 +273,112 @@ func (ap *AdditionalProperties) UnmarshalJSON(data []byte) error {
 	return err
 }
 
+// ItemsKeyword handles the "items" and "additionalItems" keywords: a schema,
+// a boolean, or up to draft 2019-09 the array of the schemas of a tuple.
+type ItemsKeyword struct {
+	Schema *Schema
+	Bool   *bool
+	Tuple  []*Schema
+}
+
+// UnmarshalJSON handles unmarshalling an ItemsKeyword from JSON.
+func (k *ItemsKeyword) UnmarshalJSON(data []byte) error {
+	var b bool
+	if err := json.Unmarshal(data, &b); err == nil {
+		k.Bool = &b
+		return nil
+	}
+	if err := json.Unmarshal(data, &k.Tuple); err == nil {
+		return nil
+	}
+	k.Tuple = nil
+	k.Schema = &Schema{}
+	return json.Unmarshal(data, k.Schema)
+}
+
+// DependencyKeyword handles a value of the "dependencies" keyword: either the
+// names of the properties required, or a schema.
+type DependencyKeyword struct {
+	Required []string
+	Schema   *Schema
+}
+
+// UnmarshalJSON handles unmarshalling a DependencyKeyword from JSON.
+func (d *DependencyKeyword) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &d.Required); err == nil {
+		return nil
+	}
+	d.Required = nil
+	d.Schema = &Schema{}
+	return json.Unmarshal(data, d.Schema)
+}
+
+// normalizeDependencies moves the parsed "dependencies" keyword to
+// DependentRequired and DependentSchemas.
+func (schema *Schema) normalizeDependencies() {
+	for property, dependency := range schema.Dependencies {
+		if dependency.Schema != nil {
+			if schema.DependentSchemas == nil {
+				schema.DependentSchemas = make(map[string]*Schema)
+			}
+			schema.DependentSchemas[property] = dependency.Schema
+			schema.dependenciesKeyword = true
+		} else {
+			if schema.DependentRequired == nil {
+				schema.DependentRequired = make(map[string][]string)
+			}
+			schema.DependentRequired[property] = dependency.Required
+		}
+	}
+	schema.Dependencies = nil
+}
+
+// normalizeItems moves the parsed "items" and "additionalItems" keywords to
+// Items, or to PrefixItems and AdditionalItems for a tuple.
+func (schema *Schema) normalizeItems() {
+	items, additionalItems := schema.RawItems, schema.RawAdditionalItems
+	if items == nil && additionalItems == nil {
+		return
+	}
+	schema.RawItems, schema.RawAdditionalItems = nil, nil
+	switch {
+	case schema.PrefixItems != nil:
+		// draft 2020-12
+		schema.prefixItemsKeyword = true
+		additionalItems = items
+	case items != nil && items.Tuple != nil:
+		// up to draft 2019-09
+		schema.PrefixItems = items.Tuple
+	default:
+		// "items": true allows any item, "items": false is not supported
+		if items != nil {
+			schema.Items = items.Schema
+		}
+		return
+	}
+	if additionalItems != nil {
+		schema.AdditionalItems = additionalItems.Schema
+		schema.AdditionalItemsBool = additionalItems.Bool
+	}
+}
+
+// tupleKeywords returns the keywords of the PrefixItems and of the
+// AdditionalItems.
+func (schema *Schema) tupleKeywords() (prefixItems string, additionalItems string) {
+	if schema.prefixItemsKeyword {
+		return "prefixItems", "items"
+	}
+	return "items", "additionalItems"
+}
+
+// dependentSchemasKeyword returns the keyword of the DependentSchemas.
+func (schema *Schema) dependentSchemasKeyword() string {
+	if schema.dependenciesKeyword {
+		return "dependencies"
+	}
+	return "dependentSchemas"
+}
+
 // ID returns the schema URI id.
 func (schema *Schema) ID() string {
 	// prefer "$id" over "id"
@@ -241,11 +501,26 @@ func (schema *Schema) updatePathElements() {
 		d.updatePathElements()
 	}
 
+	for k, d := range schema.Defs {
+		d.PathElement = "$defs/" + k
+		d.updatePathElements()
+	}
+
+	for k, d := range schema.ComponentSchemas {
+		d.PathElement = "components/schemas/" + k
+		d.updatePathElements()
+	}
+
 	for k, p := range schema.Properties {
 		p.PathElement = "properties/" + k
 		p.updatePathElements()
 	}
 
+	for k, p := range schema.PatternProperties {
+		p.PathElement = "patternProperties/" + k
+		p.updatePathElements()
+	}
+
 	if schema.AdditionalProperties != nil {
 		schema.AdditionalProperties.PathElement = "additionalProperties"
 		(*Schema)(schema.AdditionalProperties).updatePathElements()
@@ -255,20 +530,57 @@ func (schema *Schema) updatePathElements() {
 		schema.Items.PathElement = "items"
 		schema.Items.updatePathElements()
 	}
+
+	prefixItems, additionalItems := schema.tupleKeywords()
+	for i, item := range schema.PrefixItems {
+		item.PathElement = prefixItems + "/" + strconv.Itoa(i)
+		item.updatePathElements()
+	}
+	if schema.AdditionalItems != nil {
+		schema.AdditionalItems.PathElement = additionalItems
+		schema.AdditionalItems.updatePathElements()
+	}
+
+	for keyword, s := range map[string]*Schema{"if": schema.If, "then": schema.Then, "else": schema.Else} {
+		if s != nil {
+			s.PathElement = keyword
+			s.updatePathElements()
+		}
+	}
+	for k, s := range schema.DependentSchemas {
+		s.PathElement = schema.dependentSchemasKeyword() + "/" + k
+		s.updatePathElements()
+	}
 }
 
 func (schema *Schema) updateParentLinks() {
+	schema.normalizeItems()
+	schema.normalizeDependencies()
 	for k, d := range schema.Definitions {
 		d.JSONKey = k
 		d.Parent = schema
 		d.updateParentLinks()
 	}
+	for k, d := range schema.Defs {
+		d.JSONKey = k
+		d.Parent = schema
+		d.updateParentLinks()
+	}
+	for k, d := range schema.ComponentSchemas {
+		d.JSONKey = k
+		d.Parent = schema
+		d.updateParentLinks()
+	}
 
 	for k, p := range schema.Properties {
 		p.JSONKey = k
 		p.Parent = schema
 		p.updateParentLinks()
 	}
+	for _, p := range schema.PatternProperties {
+		p.Parent = schema
+		p.updateParentLinks()
+	}
 	if schema.AdditionalProperties != nil {
 		schema.AdditionalProperties.Parent = schema
 		(*Schema)(schema.AdditionalProperties).updateParentLinks()
@@ -277,6 +589,15 @@ func (schema *Schema) updateParentLinks() {
 		schema.Items.Parent = schema
 		schema.Items.updateParentLinks()
 	}
+	for i, item := range schema.PrefixItems {
+		item.JSONKey = strconv.Itoa(i)
+		item.Parent = schema
+		item.updateParentLinks()
+	}
+	if schema.AdditionalItems != nil {
+		schema.AdditionalItems.Parent = schema
+		schema.AdditionalItems.updateParentLinks()
+	}
 
 	for i, s := range schema.AllOf {
 		s.JSONKey = strconv.Itoa(i)
@@ -293,6 +614,33 @@ func (schema *Schema) updateParentLinks() {
 		s.Parent = schema
 		s.updateParentLinks()
 	}
+	for _, s := range schema.conditionalSchemas() {
+		s.Parent = schema
+		s.updateParentLinks()
+	}
+	for k, s := range schema.DependentSchemas {
+		s.JSONKey = k
+	}
+}
+
+// conditionalSchemas returns the sub-schemas of the if, then, else and
+// dependentSchemas keywords
+func (schema *Schema) conditionalSchemas() []*Schema {
+	var schemas []*Schema
+	for _, s := range []*Schema{schema.If, schema.Then, schema.Else} {
+		if s != nil {
+			schemas = append(schemas, s)
+		}
+	}
+	keys := make([]string, 0, len(schema.DependentSchemas))
+	for k := range schema.DependentSchemas {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		schemas = append(schemas, schema.DependentSchemas[k])
+	}
+	return schemas
 }
 
 func (schema *Schema) ensureSchemaKeyword() error {
@@ -307,11 +655,26 @@ func (schema *Schema) ensureSchemaKeyword() error {
 			return err
 		}
 	}
+	for k, d := range schema.Defs {
+		if err := check(k, d); err != nil {
+			return err
+		}
+	}
+	for k, d := range schema.ComponentSchemas {
+		if err := check(k, d); err != nil {
+			return err
+		}
+	}
 	for k, d := range schema.Properties {
 		if err := check(k, d); err != nil {
 			return err
 		}
 	}
+	for k, d := range schema.PatternProperties {
+		if err := check(k, d); err != nil {
+			return err
+		}
+	}
 	if schema.AdditionalProperties != nil {
 		if err := check("additionalProperties", (*Schema)(schema.AdditionalProperties)); err != nil {
 			return err
@@ -322,17 +685,32 @@ func (schema *Schema) ensureSchemaKeyword() error {
 			return err
 		}
 	}
+	for i, item := range schema.PrefixItems {
+		if err := check(strconv.Itoa(i), item); err != nil {
+			return err
+		}
+	}
+	if schema.AdditionalItems != nil {
+		if err := check("additionalItems", schema.AdditionalItems); err != nil {
+			return err
+		}
+	}
+	for _, s := range schema.conditionalSchemas() {
+		if err := check("conditional", s); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 // FixMissingTypeValue is backwards compatible, guessing the users intention when they didn't specify a type.
 func (schema *Schema) FixMissingTypeValue() {
 	if schema.TypeValue == nil {
-		if schema.Reference == "" && len(schema.Properties) > 0 {
+		if schema.Reference == "" && (len(schema.Properties) > 0 || len(schema.PatternProperties) > 0) {
 			schema.TypeValue = "object"
 			return
 		}
-		if schema.Items != nil {
+		if schema.Items != nil || len(schema.PrefixItems) > 0 {
 			schema.TypeValue = "array"
 			return
 		}
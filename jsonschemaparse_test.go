@@ -2,6 +2,7 @@ package generate
 
 import (
 	"net/url"
+	"reflect"
 	"testing"
 )
 
@@ -139,6 +140,91 @@ func TestThatDefaultsCanBeParsed(t *testing.T) {
 	}
 }
 
+func TestThatTupleItemsCanBeParsed(t *testing.T) {
+	s := `{
+        "$schema": "http://json-schema.org/draft-07/schema#",
+        "properties": {
+            "point": {
+                "items": [{ "type": "number" }, { "type": "number" }],
+                "additionalItems": false
+            },
+            "row": {
+                "prefixItems": [{ "type": "string" }],
+                "items": { "type": "integer" }
+            },
+            "list": {
+                "items": { "type": "string" }
+            }
+        }
+    }`
+	so, err := Parse(s, &url.URL{Scheme: "file", Path: "jsonschemaparse_test.go"})
+	if err != nil {
+		t.Fatal("It was not possible to unmarshal the schema:", err)
+	}
+
+	point := so.Properties["point"]
+	if len(point.PrefixItems) != 2 || point.Items != nil {
+		t.Errorf("expected the point items to be a tuple of 2 items, got %v and %v", point.PrefixItems, point.Items)
+	}
+	if point.AdditionalItemsBool == nil || *point.AdditionalItemsBool {
+		t.Errorf("expected the point additional items to be false, got %v", point.AdditionalItemsBool)
+	}
+	if point.PrefixItems[1].PathElement != "items/1" || point.PrefixItems[1].Parent != point {
+		t.Errorf("expected the point items to be at items/1, got %s", point.PrefixItems[1].PathElement)
+	}
+
+	row := so.Properties["row"]
+	if len(row.PrefixItems) != 1 || row.AdditionalItems == nil || row.Items != nil {
+		t.Errorf("expected the row items to be a tuple of 1 item, got %v and %v", row.PrefixItems, row.Items)
+	} else if typ, _ := row.AdditionalItems.Type(); typ != "integer" {
+		t.Errorf("expected the row additional items to be integers, got %s", typ)
+	}
+	if row.AdditionalItems.PathElement != "items" || row.PrefixItems[0].PathElement != "prefixItems/0" {
+		t.Errorf("expected the row items to be at prefixItems/0 and items, got %s and %s",
+			row.PrefixItems[0].PathElement, row.AdditionalItems.PathElement)
+	}
+
+	if typ, _ := so.Properties["list"].Items.Type(); typ != "string" {
+		t.Errorf("expected the list items to be strings, got %s", typ)
+	}
+}
+
+func TestThatConditionalKeywordsCanBeParsed(t *testing.T) {
+	s := `{
+        "$schema": "http://json-schema.org/draft-07/schema#",
+        "properties": {
+            "mode": { "type": "string" }
+        },
+        "if": { "properties": { "mode": { "const": "tls" } } },
+        "then": { "required": ["cert"] },
+        "dependencies": {
+            "billing": ["address"],
+            "proxy": { "required": ["proxyPort"] }
+        }
+    }`
+	so, err := Parse(s, &url.URL{Scheme: "file", Path: "jsonschemaparse_test.go"})
+	if err != nil {
+		t.Fatal("It was not possible to unmarshal the schema:", err)
+	}
+
+	if so.If == nil || so.Then == nil || so.Else != nil {
+		t.Fatalf("expected if and then schemas, got %v, %v and %v", so.If, so.Then, so.Else)
+	}
+	if so.Then.PathElement != "then" || so.Then.Parent != so {
+		t.Errorf("expected the then schema to be at then, got %s", so.Then.PathElement)
+	}
+	if expected := []string{"address"}; !reflect.DeepEqual(so.DependentRequired["billing"], expected) {
+		t.Errorf("expected the billing dependency to be %v, got %v", expected, so.DependentRequired["billing"])
+	}
+	proxy, ok := so.DependentSchemas["proxy"]
+	if !ok || so.Dependencies != nil {
+		t.Fatalf("expected the proxy dependency to be a schema, got %v", so.DependentSchemas)
+	}
+	if proxy.PathElement != "dependencies/proxy" || proxy.Parent != so {
+		t.Errorf("expected the proxy dependency to be at dependencies/proxy, got %s", proxy.PathElement)
+	}
+}
+
 func TestReturnedSchemaId(t *testing.T) {
 	tests := []struct {
 		input    *Schema
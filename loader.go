@@ -0,0 +1,147 @@
+package generate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Loader loads the schema documents referenced by URI.
+type Loader interface {
+	// Load returns the content of the document, the uri has no fragment.
+	Load(uri *url.URL) ([]byte, error)
+}
+
+// DefaultHTTPTimeout is the time limit of the requests of an HTTPLoader with
+// no client.
+const DefaultHTTPTimeout = 30 * time.Second
+
+// NewDefaultLoader returns a loader of the file URIs.
+func NewDefaultLoader() Loader {
+	return SchemeLoader{
+		"file": FileLoader{},
+	}
+}
+
+// NewHTTPEnabledLoader returns a loader of the file, http and https URIs, the
+// requests timing out after timeout.
+func NewHTTPEnabledLoader(timeout time.Duration) Loader {
+	httpLoader := &HTTPLoader{Client: &http.Client{Timeout: timeout}}
+	return SchemeLoader{
+		"file":  FileLoader{},
+		"http":  httpLoader,
+		"https": httpLoader,
+	}
+}
+
+// SchemeLoader dispatches the URIs to a loader by scheme.
+type SchemeLoader map[string]Loader
+
+// Load the document with the loader of the uri scheme.
+func (l SchemeLoader) Load(uri *url.URL) ([]byte, error) {
+	loader, ok := l[uri.Scheme]
+	if !ok {
+		return nil, fmt.Errorf("no loader for the %q scheme of %s", uri.Scheme, uri)
+	}
+	return loader.Load(uri)
+}
+
+// FileLoader loads the file URIs from disk.
+type FileLoader struct{}
+
+// Load reads the file at the uri path.
+func (FileLoader) Load(uri *url.URL) ([]byte, error) {
+	if uri.Scheme != "file" {
+		return nil, fmt.Errorf("not a file URI: %s", uri)
+	}
+	return ioutil.ReadFile(uri.Path)
+}
+
+// HTTPLoader loads the http and https URIs.
+type HTTPLoader struct {
+	// Client is used for the requests, a client with the DefaultHTTPTimeout
+	// if nil.
+	Client *http.Client
+}
+
+// Load fetches the document with a GET request.
+func (l *HTTPLoader) Load(uri *url.URL) ([]byte, error) {
+	client := l.Client
+	if client == nil {
+		client = &http.Client{Timeout: DefaultHTTPTimeout}
+	}
+	resp, err := client.Get(uri.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get %s: %s", uri, resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// PrefixLoader maps URI prefixes to local directories, so that documents can
+// be loaded from a vendored copy while keeping their $id.
+type PrefixLoader struct {
+	// Prefixes maps a URI prefix, e.g. "https://schemas.example.com/", to a
+	// directory.
+	Prefixes map[string]string
+	// Loader loads the URIs matching no prefix, if not nil.
+	Loader Loader
+}
+
+// Load reads the document from the directory of the longest matching prefix.
+func (l *PrefixLoader) Load(uri *url.URL) ([]byte, error) {
+	s := uri.String()
+	prefix := ""
+	for p := range l.Prefixes {
+		if strings.HasPrefix(s, p) && len(p) > len(prefix) {
+			prefix = p
+		}
+	}
+	if prefix == "" {
+		if l.Loader == nil {
+			return nil, fmt.Errorf("no directory mapped to %s", uri)
+		}
+		return l.Loader.Load(uri)
+	}
+	return ioutil.ReadFile(filepath.Join(l.Prefixes[prefix], filepath.FromSlash(s[len(prefix):])))
+}
+
+// CachingLoader keeps a copy of the loaded documents in a directory and loads
+// them from there afterwards. The file URIs are not cached.
+type CachingLoader struct {
+	Dir    string
+	Loader Loader
+}
+
+// Load returns the cached document, loading it first if needed.
+func (l *CachingLoader) Load(uri *url.URL) ([]byte, error) {
+	if uri.Scheme == "file" {
+		return l.Loader.Load(uri)
+	}
+	sum := sha256.Sum256([]byte(uri.String()))
+	cached := filepath.Join(l.Dir, hex.EncodeToString(sum[:])+".json")
+	if b, err := ioutil.ReadFile(cached); err == nil {
+		return b, nil
+	}
+	b, err := l.Loader.Load(uri)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(l.Dir, 0755); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(cached, b, 0644); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
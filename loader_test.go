@@ -0,0 +1,98 @@
+package generate
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type mapLoader map[string]string
+
+func (l mapLoader) Load(uri *url.URL) ([]byte, error) {
+	if s, ok := l[uri.String()]; ok {
+		return []byte(s), nil
+	}
+	return nil, fmt.Errorf("not found: %s", uri)
+}
+
+func TestLoaderResolvesReferences(t *testing.T) {
+	root, err := Parse(`{
+		"$schema": "http://json-schema.org/schema#",
+		"title": "Order",
+		"type": "object",
+		"properties": {
+			"customer": {"$ref": "urn:example:customer#/definitions/customer"}
+		}
+	}`, &url.URL{Scheme: "file", Path: "/order.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := New(root)
+	g.Loader = mapLoader{
+		"urn:example:customer": `{
+			"$schema": "http://json-schema.org/schema#",
+			"definitions": {
+				"customer": {"type": "object", "properties": {"name": {"type": "string"}}}
+			}
+		}`,
+	}
+	if err := g.CreateTypes(); err != nil {
+		t.Fatal("Failed to create structs: ", err)
+	}
+	testField(g.Structs["Order"].Fields["Customer"], "customer", "Customer", "*Customer", false, t)
+
+	g = New(root)
+	g.Loader = nil
+	if err := g.CreateTypes(); err == nil {
+		t.Error("Expected an error when no loader is set")
+	}
+}
+
+func TestPrefixAndCachingLoaders(t *testing.T) {
+	dir, err := ioutil.TempDir("", "loader")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "a.json"), []byte(`{"title": "a"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	prefixLoader := &PrefixLoader{Prefixes: map[string]string{"https://schemas.example.com/": dir}}
+	b, err := prefixLoader.Load(&url.URL{Scheme: "https", Host: "schemas.example.com", Path: "/a.json"})
+	if err != nil || string(b) != `{"title": "a"}` {
+		t.Errorf("Unexpected mapped document %q (%v)", b, err)
+	}
+	if _, err := prefixLoader.Load(&url.URL{Scheme: "https", Host: "other.example.com", Path: "/a.json"}); err == nil {
+		t.Error("Expected an error for a URI matching no prefix")
+	}
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"title": "remote"}`)
+	}))
+	defer server.Close()
+
+	uri, _ := url.Parse(server.URL + "/remote.json")
+	if _, err := NewDefaultLoader().Load(uri); err == nil {
+		t.Error("Expected the default loader not to load http URIs")
+	}
+	cachingLoader := &CachingLoader{Dir: filepath.Join(dir, "cache"), Loader: NewHTTPEnabledLoader(time.Second)}
+	for i := 0; i < 2; i++ {
+		b, err := cachingLoader.Load(uri)
+		if err != nil || string(b) != `{"title": "remote"}` {
+			t.Errorf("Unexpected remote document %q (%v)", b, err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Expected the document to be fetched once, got %d requests", requests)
+	}
+}
@@ -0,0 +1,48 @@
+package generate
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+)
+
+// openAPIDocument is the part of an OpenAPI 3 document the types are
+// generated from.
+type openAPIDocument struct {
+	OpenAPI    string `json:"openapi"`
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas"`
+	} `json:"components"`
+}
+
+// isOpenAPI returns true if the JSON document is an OpenAPI document.
+func isOpenAPI(b []byte) bool {
+	var doc struct {
+		OpenAPI string `json:"openapi"`
+	}
+	return json.Unmarshal(b, &doc) == nil && doc.OpenAPI != ""
+}
+
+// ParseOpenAPI parses an OpenAPI 3 document from a string. The returned schema
+// has the "components/schemas" of the document, and is not a type by itself.
+func ParseOpenAPI(doc string, uri *url.URL) (*Schema, error) {
+	var d openAPIDocument
+	if err := json.Unmarshal([]byte(doc), &d); err != nil {
+		return nil, err
+	}
+	if d.OpenAPI == "" {
+		return nil, errors.New("OpenAPI document must have an openapi key: \"" + uri.String() + "\"")
+	}
+	if !uri.IsAbs() {
+		return nil, errors.New("URI of OpenAPI document not absolute: \"" + uri.String() + "\"")
+	}
+
+	s := &Schema{
+		ID06:             uri.String(),
+		OpenAPI:          d.OpenAPI,
+		ComponentSchemas: d.Components.Schemas,
+	}
+	s.Init()
+
+	return s, nil
+}
@@ -2,9 +2,23 @@ package generate
 
 import (
 	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build/constraint"
+	"go/format"
+	"go/parser"
+	"go/scanner"
+	"go/token"
 	"io"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"text/template"
+	"unicode"
 )
 
 func getOrderedFieldNames(m map[string]Field) []string {
@@ -29,6 +43,33 @@ func getOrderedStructNames(m map[string]Struct) []string {
 	return keys
 }
 
+func getOrderedEnumNames(m map[string]Enum) []string {
+	keys := make([]string, len(m))
+	idx := 0
+	for k := range m {
+		keys[idx] = k
+		idx++
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// typePackages maps the package names used in the generated types to their
+// import paths
+var typePackages = map[string]string{
+	"decimal": "github.com/shopspring/decimal",
+	"json":    "encoding/json",
+	"net":     "net",
+	"time":    "time",
+	"url":     "net/url",
+}
+
+var qualifiedTypeRegexp = regexp.MustCompile(`([a-z]+)\.[A-Z]`)
+
+// uuidTypeRegexp matches the types using the UUID helper type, e.g.
+// "[]UUID", "map[string]UUID" or "Nullable[UUID]"
+var uuidTypeRegexp = regexp.MustCompile(`(^|[^.\w])UUID\b`)
+
 // OutputData contains all the data necessary for the template
 type OutputData struct {
 	ImportPaths map[string]string
@@ -37,10 +78,29 @@ type OutputData struct {
 	Structs     []Struct
 	Aliases     []Field
 	OneOfs      map[string]OneOf
+	AnyOfs      map[string]AnyOf
+	Enums       []Enum
 	Backquote   string
 	EmptyTypes  map[string]string
 
 	AlwaysAcceptFalse bool
+	AnyOfAllMatches   bool
+	// Header are the lines of the comment written at the top of the code
+	Header []string
+	// BuildConstraints are the "//go:build" and "// +build" lines
+	BuildConstraints []string
+	// UUID is true if the generated types use the UUID helper type
+	UUID bool
+	// Nullable is true if the generated types use the Nullable helper type
+	Nullable bool
+
+	// the names of all the enums, including the ones of other files
+	enumNames map[string]bool
+	// the templates, including the overrides
+	templates *template.Template
+	// the struct tags by name, with their naming strategy
+	tags         map[string]string
+	validateTags bool
 }
 
 // Pkg ...
@@ -62,28 +122,334 @@ func (d *OutputData) Pkg(name string, path ...string) string {
 	return name
 }
 
+// useType registers the imports and helpers a generated type needs
+func (d *OutputData) useType(typ string) {
+	for _, m := range qualifiedTypeRegexp.FindAllStringSubmatch(typ, -1) {
+		if path, ok := typePackages[m[1]]; ok {
+			d.Pkg(path)
+		}
+	}
+	if uuidTypeRegexp.MatchString(typ) {
+		d.UUID = true
+	}
+	if strings.Contains(typ, "Nullable[") {
+		d.Nullable = true
+	}
+}
+
+// IsEnum returns true if the given type is a generated enum
+func (d *OutputData) IsEnum(t string) bool {
+	return d.enumNames[t]
+}
+
+// TemplateData is the data the named sub-templates of the types are executed
+// with: the output data and the type or field being generated.
+type TemplateData struct {
+	*OutputData
+
+	Struct Struct
+	Field  Field
+	Alias  Field
+	OneOf  OneOf
+	AnyOf  AnyOf
+	Enum   Enum
+}
+
+// ForStruct returns the template data of a struct
+func (d *OutputData) ForStruct(s Struct) TemplateData {
+	return TemplateData{OutputData: d, Struct: s}
+}
+
+// ForField returns the template data of a struct field
+func (d *OutputData) ForField(s Struct, f Field) TemplateData {
+	return TemplateData{OutputData: d, Struct: s, Field: f}
+}
+
+// ForAlias returns the template data of an alias
+func (d *OutputData) ForAlias(a Field) TemplateData {
+	return TemplateData{OutputData: d, Alias: a}
+}
+
+// ForOneOf returns the template data of a oneOf type
+func (d *OutputData) ForOneOf(o OneOf) TemplateData {
+	return TemplateData{OutputData: d, OneOf: o}
+}
+
+// ForAnyOf returns the template data of an anyOf type
+func (d *OutputData) ForAnyOf(a AnyOf) TemplateData {
+	return TemplateData{OutputData: d, AnyOf: a}
+}
+
+// ForEnum returns the template data of an enum
+func (d *OutputData) ForEnum(e Enum) TemplateData {
+	return TemplateData{OutputData: d, Enum: e}
+}
+
 // NoProp returns true if the struct has no property
 func (s Struct) NoProp() bool {
 	return len(s.Fields) == 0 && (s.AdditionalType == "" || s.AdditionalType == "false")
 }
 
+// TupleItems returns the fields of the items of a tuple, in order
+func (s Struct) TupleItems() []Field {
+	fields := make([]Field, len(s.TupleFields))
+	for i, name := range s.TupleFields {
+		fields[i] = s.Fields[name]
+	}
+	return fields
+}
+
+// TupleMinItems returns the number of items a tuple must have at least
+func (s Struct) TupleMinItems() int {
+	n := 0
+	for i, f := range s.TupleItems() {
+		if f.Required {
+			n = i + 1
+		}
+	}
+	return n
+}
+
 // IsPointer returns true if the type is a pointer
 func (f Field) IsPointer() bool {
 	return strings.HasPrefix(f.Type, "*")
 }
 
+// OutputOptions are the options of the generated code.
+type OutputOptions struct {
+	// PackageName is the package of the generated code
+	PackageName string
+	// AlwaysAcceptFalse makes any field accept decoding 'false' and ignore it
+	AlwaysAcceptFalse bool
+	// UseEmptyTypes uses the Empty* types for the non-required fields
+	UseEmptyTypes bool
+	// AnyOfAllMatches records all the anyOf types matching a decoded value
+	AnyOfAllMatches bool
+	// Header is written as a comment at the top of the code, e.g. a license
+	Header string
+	// BuildTags is a build constraint expression, e.g. "linux && !appengine"
+	BuildTags string
+	// ImportAliases maps import paths to the names they are imported as
+	ImportAliases map[string]string
+	// NoHelpers omits the runtime helpers, so that several generated files
+	// can share a package. They must be generated once with OutputHelpers.
+	NoHelpers bool
+	// Helpers are the optional runtime helpers OutputHelpers writes, that the
+	// types generated with NoHelpers may use: "Nullable" for the null values,
+	// requiring Go 1.18, and "UUID" for the "uuid" format
+	Helpers []string
+	// TemplateDir is a directory of "*.tmpl" files overriding the named
+	// sub-templates of the types, e.g. {{ define "field" }}...{{ end }}
+	TemplateDir string
+	// StdJSON generates JSON marshalling methods using encoding/json only,
+	// instead of github.com/json-iterator/go
+	StdJSON bool
+	// Tags are the struct tags added to the fields besides the json one, with
+	// the naming strategy of their JSON names: "json" (the JSON name itself),
+	// "snake_case", "camelCase", "PascalCase" or "kebab-case"
+	Tags map[string]string
+	// ValidateTags adds the go-playground/validator tags of the validation
+	// keywords to the fields
+	ValidateTags bool
+}
+
 // Output generates code and writes to w.
+//
+// Deprecated: use OutputWithOptions, Output panics on errors.
 func Output(w io.Writer, g *Generator, pkg string, alwaysAcceptFalse bool, useEmptyTypes bool) {
+	err := OutputWithOptions(w, g, OutputOptions{
+		PackageName:       pkg,
+		AlwaysAcceptFalse: alwaysAcceptFalse,
+		UseEmptyTypes:     useEmptyTypes,
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// OutputWithOptions generates code and writes it to w.
+func OutputWithOptions(w io.Writer, g *Generator, opts OutputOptions) error {
+	data, err := newOutputData(g, opts)
+	if err != nil {
+		return err
+	}
+	tmpl := data.templates
+	if opts.NoHelpers {
+		tmpl = data.templates.Lookup("types")
+		if err := data.useHelpersImports(); err != nil {
+			return err
+		}
+	}
+	code, err := data.render(tmpl, opts.ImportAliases)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(code)
+	return err
+}
+
+// OutputHelpers writes the runtime helpers alone, for the packages the types
+// are generated in with the NoHelpers option. The optional helpers are only
+// written if they are in opts.Helpers.
+func OutputHelpers(w io.Writer, opts OutputOptions) error {
+	data, err := newOutputData(New(), opts)
+	if err != nil {
+		return err
+	}
+	for _, helper := range opts.Helpers {
+		switch helper {
+		case "Nullable":
+			data.Nullable = true
+		case "UUID":
+			data.UUID = true
+		default:
+			return fmt.Errorf("unknown helper %q", helper)
+		}
+	}
+	code, err := data.render(data.templates.Lookup("helpers"), opts.ImportAliases)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(code)
+	return err
+}
+
+// OutputFiles generates the code of the types of each input document in a
+// file named after it, or of each type in its own file if perType is true,
+// and the runtime helpers in a "helpers.go" file. It returns the code by file
+// name.
+func OutputFiles(g *Generator, opts OutputOptions, perType bool) (map[string][]byte, error) {
+	data, err := newOutputData(g, opts)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string][]byte)
+	if !opts.NoHelpers {
+		if files["helpers.go"], err = data.subset(nil).render(data.templates.Lookup("helpers"), opts.ImportAliases); err != nil {
+			return nil, err
+		}
+	}
+	if err := data.useHelpersImports(); err != nil {
+		return nil, err
+	}
+
+	// the file name of each type
+	sourceFiles := make(map[string]string)
+	usedFiles := map[string]bool{"helpers.go": true}
+	fileOf := func(typeName string) string {
+		if perType {
+			return goFileName(typeName)
+		}
+		source := g.sources[typeName]
+		if file, ok := sourceFiles[source]; ok {
+			return file
+		}
+		file := goFileName(documentName(source))
+		// documents with the same name in different directories
+		for i := 2; usedFiles[file]; i++ {
+			file = goFileName(fmt.Sprintf("%s%d", documentName(source), i))
+		}
+		sourceFiles[source] = file
+		usedFiles[file] = true
+		return file
+	}
+	typeNames := make(map[string][]string)
+	add := func(typeName string) {
+		file := fileOf(typeName)
+		typeNames[file] = append(typeNames[file], typeName)
+	}
+	for _, s := range data.Structs {
+		add(s.Name)
+	}
+	for _, a := range data.Aliases {
+		add(a.Name)
+	}
+	for name := range data.OneOfs {
+		add(name)
+	}
+	for name := range data.AnyOfs {
+		add(name)
+	}
+	for _, e := range data.Enums {
+		add(e.Name)
+	}
+
+	for file, names := range typeNames {
+		if files[file], err = data.subset(names).render(data.templates.Lookup("types"), opts.ImportAliases); err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+	}
+	return files, nil
+}
+
+// documentName returns the name of a document without the extension, e.g.
+// "address" for "file:///schemas/address.json".
+func documentName(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "types"
+	}
+	name := u.Path
+	if name == "" {
+		name = u.Opaque
+	}
+	name = name[strings.LastIndexAny(name, "/:")+1:]
+	if i := strings.Index(name, "."); i != -1 {
+		name = name[:i]
+	}
+	if name == "" {
+		return "types"
+	}
+	return name
+}
+
+// goFileName returns a snake case go file name, e.g. "order_line.go" for
+// "OrderLine".
+func goFileName(name string) string {
+	var buf bytes.Buffer
+	var previous rune
+	for _, r := range name {
+		switch {
+		case unicode.IsUpper(r):
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) {
+				buf.WriteByte('_')
+			}
+			buf.WriteRune(unicode.ToLower(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			buf.WriteRune(r)
+		default:
+			r = '_'
+			if previous != '_' {
+				buf.WriteRune(r)
+			}
+		}
+		previous = r
+	}
+	name = strings.Trim(buf.String(), "_")
+	// keep clear of the helpers and of the files the go tool handles specially
+	if name == "" || name == "helpers" || strings.HasSuffix(name, "_test") {
+		name += "_types"
+	}
+	return name + ".go"
+}
+
+// newOutputData returns the template data of all the generated types.
+func newOutputData(g *Generator, opts OutputOptions) (*OutputData, error) {
 	structs := g.Structs
 	aliases := g.Aliases
 
 	data := OutputData{
 		ImportPaths: make(map[string]string),
 
-		PackageName:       cleanPackageName(pkg),
+		PackageName:       cleanPackageName(opts.PackageName),
 		OneOfs:            g.OneOfs,
+		AnyOfs:            g.AnyOfs,
 		Backquote:         "`",
-		AlwaysAcceptFalse: alwaysAcceptFalse,
+		AlwaysAcceptFalse: opts.AlwaysAcceptFalse,
+		AnyOfAllMatches:   opts.AnyOfAllMatches,
+
+		tags:         opts.Tags,
+		validateTags: opts.ValidateTags,
 
 		EmptyTypes: map[string]string{
 			"string":  "EmptyString",
@@ -92,10 +458,28 @@ func Output(w io.Writer, g *Generator, pkg string, alwaysAcceptFalse bool, useEm
 			"float64": "EmptyFloat64",
 		},
 	}
+	if err := checkTagNamings(opts.Tags); err != nil {
+		return nil, err
+	}
+	if opts.Header != "" {
+		data.Header = strings.Split(strings.TrimRight(opts.Header, "\n"), "\n")
+	}
+	if opts.BuildTags != "" {
+		expr, err := constraint.Parse("//go:build " + opts.BuildTags)
+		if err != nil {
+			return nil, fmt.Errorf("invalid build tags %q: %v", opts.BuildTags, err)
+		}
+		data.BuildConstraints = append(data.BuildConstraints, "//go:build "+expr.String())
+		plusBuild, err := constraint.PlusBuildLines(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid build tags %q: %v", opts.BuildTags, err)
+		}
+		data.BuildConstraints = append(data.BuildConstraints, plusBuild...)
+	}
 
 	for _, k := range getOrderedStructNames(structs) {
 		s := structs[k]
-		if useEmptyTypes {
+		if opts.UseEmptyTypes {
 			for n, f := range s.Fields {
 				if !f.Required {
 					if t, ok := data.EmptyTypes[f.Type]; ok {
@@ -105,24 +489,224 @@ func Output(w io.Writer, g *Generator, pkg string, alwaysAcceptFalse bool, useEm
 				}
 			}
 		}
+		for _, f := range s.Fields {
+			data.useType(f.Type)
+		}
 		data.Structs = append(data.Structs, s)
 	}
+	for _, o := range g.OneOfs {
+		for _, t := range o.Types {
+			data.useType(t.Type)
+		}
+	}
+	for _, o := range g.AnyOfs {
+		for _, t := range o.Types {
+			data.useType(t.Type)
+		}
+	}
+
+	for _, k := range getOrderedEnumNames(g.Enums) {
+		data.Enums = append(data.Enums, g.Enums[k])
+	}
 
 	for _, k := range getOrderedFieldNames(aliases) {
+		data.useType(aliases[k].Type)
 		data.Aliases = append(data.Aliases, aliases[k])
 	}
 
+	var err error
+	if data.templates, err = loadTemplates(opts.TemplateDir, opts.StdJSON); err != nil {
+		return nil, err
+	}
+
+	data.enumNames = make(map[string]bool, len(data.Enums))
+	for _, e := range data.Enums {
+		data.enumNames[e.Name] = true
+	}
+
+	return &data, nil
+}
+
+// useHelpersImports registers the packages imported by the helpers, that
+// the types use too. The unused imports are pruned afterwards.
+func (d *OutputData) useHelpersImports() error {
+	return d.templates.ExecuteTemplate(ioutil.Discard, "helpers", d)
+}
+
+// loadTemplates returns the templates, using encoding/json only if stdJSON is
+// true, with the sub-templates overridden by the template files of dir.
+func loadTemplates(dir string, stdJSON bool) (*template.Template, error) {
+	base := mainTmpl
+	if stdJSON {
+		base = stdJSONTmpl
+	}
+	if dir == "" {
+		return base, nil
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no template file in %s", dir)
+	}
+	tmpl, err := base.Clone()
+	if err != nil {
+		return nil, err
+	}
+	return tmpl.ParseFiles(files...)
+}
+
+// subset returns the data of the given types only.
+func (d *OutputData) subset(typeNames []string) *OutputData {
+	sub := *d
+	sub.ImportPaths = make(map[string]string, len(d.ImportPaths))
+	for path, name := range d.ImportPaths {
+		sub.ImportPaths[path] = name
+	}
+	sub.Structs = nil
+	for _, s := range d.Structs {
+		if contains(typeNames, s.Name) {
+			sub.Structs = append(sub.Structs, s)
+		}
+	}
+	sub.Aliases = nil
+	for _, a := range d.Aliases {
+		if contains(typeNames, a.Name) {
+			sub.Aliases = append(sub.Aliases, a)
+		}
+	}
+	sub.OneOfs = make(map[string]OneOf)
+	for name, o := range d.OneOfs {
+		if contains(typeNames, name) {
+			sub.OneOfs[name] = o
+		}
+	}
+	sub.AnyOfs = make(map[string]AnyOf)
+	for name, a := range d.AnyOfs {
+		if contains(typeNames, name) {
+			sub.AnyOfs[name] = a
+		}
+	}
+	sub.Enums = nil
+	for _, e := range d.Enums {
+		if contains(typeNames, e.Name) {
+			sub.Enums = append(sub.Enums, e)
+		}
+	}
+	return &sub
+}
+
+// render executes the template and returns the formatted code.
+func (d *OutputData) render(tmpl *template.Template, importAliases map[string]string) ([]byte, error) {
 	codeBuf := new(bytes.Buffer)
 
-	if err := mainTmpl.Execute(codeBuf, &data); err != nil {
-		panic(err)
+	if err := tmpl.Execute(codeBuf, d); err != nil {
+		return nil, err
 	}
 
-	if err := headerTmpl.Execute(w, &data); err != nil {
-		panic(err)
+	buf := new(bytes.Buffer)
+	if err := headerTmpl.Execute(buf, d); err != nil {
+		return nil, err
+	}
+	buf.Write(codeBuf.Bytes())
+
+	return formatCode(buf.Bytes(), importAliases)
+}
+
+// formatCode removes the unused imports of the code, renames the imports that
+// have an alias, and formats it.
+func formatCode(code []byte, aliases map[string]string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", code, parser.ParseComments)
+	if err != nil {
+		return nil, syntaxError(code, err)
+	}
+
+	// the identifiers that are not declared in the file are packages
+	packageRefs := make(map[string][]*ast.Ident)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok && id.Obj == nil {
+				packageRefs[id.Name] = append(packageRefs[id.Name], id)
+			}
+		}
+		return true
+	})
+
+	var imports []*ast.ImportSpec
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			return nil, err
+		}
+		name := path[strings.LastIndex(path, "/")+1:]
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if len(packageRefs[name]) == 0 {
+			continue
+		}
+		if alias, ok := aliases[path]; ok {
+			spec.Name = ast.NewIdent(alias)
+			for _, id := range packageRefs[name] {
+				id.Name = alias
+			}
+		}
+		imports = append(imports, spec)
+	}
+	used := make(map[ast.Spec]bool, len(imports))
+	for _, spec := range imports {
+		used[spec] = true
 	}
+	decls := file.Decls[:0]
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			specs := gen.Specs[:0]
+			for _, spec := range gen.Specs {
+				if used[spec] {
+					specs = append(specs, spec)
+				}
+			}
+			gen.Specs = specs
+			// the declarations with no used imports would be printed as "import ()"
+			if len(gen.Specs) == 0 {
+				continue
+			}
+		}
+		decls = append(decls, decl)
+	}
+	file.Decls = decls
+	file.Imports = imports
+
+	buf := new(bytes.Buffer)
+	if err := format.Node(buf, fset, file); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
 
-	w.Write(codeBuf.Bytes())
+// syntaxError returns the error of the generated code parsing with the
+// lines around the error.
+func syntaxError(code []byte, err error) error {
+	list, ok := err.(scanner.ErrorList)
+	if !ok || len(list) == 0 {
+		return fmt.Errorf("syntax error in the generated code: %v", err)
+	}
+	lines := strings.Split(string(code), "\n")
+	line := list[0].Pos.Line
+	snippet := new(bytes.Buffer)
+	for i := line - 3; i <= line+3; i++ {
+		if i < 1 || i > len(lines) {
+			continue
+		}
+		marker := " "
+		if i == line {
+			marker = ">"
+		}
+		fmt.Fprintf(snippet, "%s%5d: %s\n", marker, i, lines[i-1])
+	}
+	return fmt.Errorf("syntax error in the generated code: %v\n%s", list[0], snippet)
 }
 
 func cleanPackageName(pkg string) string {
@@ -0,0 +1,891 @@
+package generate
+
+import (
+	"text/template"
+)
+
+// stdJSONTmpl generates the same types as mainTmpl, with JSON marshalling
+// methods using encoding/json only instead of jsoniter.
+var stdJSONTmpl *template.Template
+
+func init() {
+	// mainTmpl must have all its sub-templates before it is cloned
+	stdJSONTmpl = template.Must(template.Must(mainTmpl.Clone()).Parse(stdJSONTemplates))
+}
+
+// stdJSONTemplates replace the sub-templates of mainTmpl using jsoniter
+const stdJSONTemplates = `
+{{- /* the JSON runtime helpers */ -}}
+{{- define "jsonHelpers" }}
+{{- with $top := . }}
+
+// jsonValueType is the type of a JSON value
+type jsonValueType int
+
+const (
+	jsonInvalidValue jsonValueType = iota
+	jsonStringValue
+	jsonNumberValue
+	jsonNilValue
+	jsonBoolValue
+	jsonArrayValue
+	jsonObjectValue
+)
+
+func (t jsonValueType) String() string {
+	switch t {
+	case jsonStringValue:
+		return "string"
+	case jsonNumberValue:
+		return "number"
+	case jsonNilValue:
+		return "nil"
+	case jsonBoolValue:
+		return "bool"
+	case jsonArrayValue:
+		return "array"
+	case jsonObjectValue:
+		return "object"
+	default:
+		return "invalid"
+	}
+}
+
+// whatIsNext returns the type of the JSON value in data
+func whatIsNext(data []byte) jsonValueType {
+	data = {{ .Pkg "bytes" }}.TrimLeft(data, " \t\r\n")
+	if len(data) == 0 {
+		return jsonInvalidValue
+	}
+	switch data[0] {
+	case '"':
+		return jsonStringValue
+	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return jsonNumberValue
+	case 'n':
+		return jsonNilValue
+	case 't', 'f':
+		return jsonBoolValue
+	case '[':
+		return jsonArrayValue
+	case '{':
+		return jsonObjectValue
+	}
+	return jsonInvalidValue
+}
+
+// objectWriter writes a JSON object member by member
+type objectWriter struct {
+	buf     bytes.Buffer
+	started bool
+}
+
+// Field writes a member with the JSON encoding of value
+func (w *objectWriter) Field(key string, value interface{}) error {
+	b, err := {{ .Pkg "encoding/json" }}.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if w.started {
+		w.buf.WriteByte(',')
+	} else {
+		w.buf.WriteByte('{')
+		w.started = true
+	}
+	k, _ := json.Marshal(key)
+	w.buf.Write(k)
+	w.buf.WriteByte(':')
+	w.buf.Write(b)
+	return nil
+}
+
+// Bytes returns the JSON object
+func (w *objectWriter) Bytes() []byte {
+	if !w.started {
+		return []byte("{}")
+	}
+	w.buf.WriteByte('}')
+	return w.buf.Bytes()
+}
+
+// readObject calls readField with each member of the JSON object in data, in
+// order. A 'null' is read as an empty object.
+func readObject(data []byte, readField func(field string, value json.RawMessage) error) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		return nil
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return {{ .Pkg "fmt" }}.Errorf("expected an object, got %v", token)
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+		if err := readField(token.(string), value); err != nil {
+			return err
+		}
+	}
+	_, err = decoder.Token()
+	return err
+}
+
+{{- range $t, $tname := .EmptyTypes }}
+
+// New{{ $tname }} creates a non-empty {{ $tname }}
+func New{{ $tname }}(value {{ $t }}) {{ $tname }} {
+	return {{ $tname }}{value, true}
+}
+
+// {{ $tname }} is {{ $t }} or nothing
+type {{ $tname }} struct {
+	{{ capitalize $t }} {{ $t }}
+	Valid bool // Valid is true if {{ capitalize $t }} is not empty
+}
+
+func (t {{ $tname }}) IsEmpty() bool {
+	return !t.Valid
+}
+
+func (t {{ $tname }}) MarshalJSON() ([]byte, error) {
+	if t.Valid {
+		return json.Marshal(t.{{ capitalize $t }})
+	}
+	return []byte("\"\""), nil
+}
+
+func (t *{{ $tname }}) Set(value {{ $t }}) {
+	t.{{ capitalize $t }} = value
+	t.Valid = true
+}
+
+func (t *{{ $tname }}) Unset() {
+	{{- if eq $t "string" }}
+	t.String = ""
+	{{- else if eq $t "bool" }}
+	t.Bool = false
+	{{- else if eq $t "int" }}
+	t.Int = 0
+	{{- else if eq $t "float64" }}
+	t.Float64 = 0.0
+	{{- end}}
+	t.Valid = false
+}
+
+func (t *{{ $tname }}) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &t.{{ capitalize $t }}); err != nil {
+		return err
+	}
+	t.Valid = true
+	return nil
+}
+{{- end }}
+
+// OneOfStringNull is a 'string' or a 'null', and can be emptied
+type OneOfStringNull struct {
+	currentType jsonValueType
+	stringValue string
+}
+
+// NewOneOfStringNull creates a empty OneOfStringNull
+func NewOneOfStringNull() OneOfStringNull {
+	return OneOfStringNull{jsonInvalidValue, ""}
+}
+
+// NewOneOfStringNullString creates a OneOfStringNull of type string
+func NewOneOfStringNullString(value string) OneOfStringNull {
+	return OneOfStringNull{jsonStringValue, value}
+}
+
+// NewOneOfStringNullNull creates a OneOfStringNull of type null
+func NewOneOfStringNullNull() OneOfStringNull {
+	return OneOfStringNull{jsonNilValue, ""}
+}
+
+// IsEmpty returns true if the value is empty
+func (value *OneOfStringNull) IsEmpty() bool {
+	return value.currentType == jsonInvalidValue
+}
+
+// IsNull returns true if the value is 'null'
+func (value *OneOfStringNull) IsNull() bool {
+	return value.currentType == jsonNilValue
+}
+
+// IsString returns true if the value is a string
+func (value *OneOfStringNull) IsString() bool {
+	return value.currentType == jsonStringValue
+}
+
+// StringValue returns the current value if IsString() is true, "" otherwise
+func (value *OneOfStringNull) StringValue() string {
+	if value.currentType == jsonStringValue {
+		return value.stringValue
+	}
+	return ""
+}
+
+// NullString returns the current value as a sql.NullString
+func (value *OneOfStringNull) NullString() {{ .Pkg "database/sql" }}.NullString {
+	return sql.NullString{
+		Valid:  value.currentType == jsonStringValue,
+		String: value.stringValue,
+	}
+}
+
+// MarshalJSON serialize to json
+func (value OneOfStringNull) MarshalJSON() ([]byte, error) {
+	switch value.currentType {
+	case jsonInvalidValue:
+		return jsonNullValue, nil
+	case jsonNilValue:
+		return jsonNullValue, nil
+	case jsonStringValue:
+		return json.Marshal(value.stringValue)
+	}
+	return nil, fmt.Errorf(
+		"OneOfStringNull unsupported type: %s",
+		value.currentType)
+}
+
+// UnmarshalJSON unserialize a OneOfStringNull from json
+func (value *OneOfStringNull) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, jsonNullValue) {
+		value.currentType = jsonNilValue
+	} else {
+		if err := json.Unmarshal(data, &value.stringValue); err != nil {
+			return err
+		}
+		value.currentType = jsonStringValue
+	}
+	return nil
+}
+
+// OneOfNumberNull is a 'string' or a 'null', and can be emptied
+type OneOfNumberNull struct {
+	currentType jsonValueType
+	numberValue float64
+}
+
+// NewOneOfNumberNull creates a empty OneOfNumberNull
+func NewOneOfNumberNull() OneOfNumberNull {
+	return OneOfNumberNull{jsonInvalidValue, 0}
+}
+
+// NewOneOfNumberNullNumber creates a OneOfNumberNull of type number
+func NewOneOfNumberNullNumber(value float64) OneOfNumberNull {
+	return OneOfNumberNull{jsonNumberValue, value}
+}
+
+// NewOneOfNumberNullNull creates a OneOfNumberNull of type null
+func NewOneOfNumberNullNull() OneOfNumberNull {
+	return OneOfNumberNull{jsonNilValue, 0}
+}
+
+// IsEmpty returns true if the value is empty
+func (value *OneOfNumberNull) IsEmpty() bool {
+	return value.currentType == jsonInvalidValue
+}
+
+// IsNull returns true if the value is 'null'
+func (value *OneOfNumberNull) IsNull() bool {
+	return value.currentType == jsonNilValue
+}
+
+// IsNumber returns true if the value is a number
+func (value *OneOfNumberNull) IsNumber() bool {
+	return value.currentType == jsonNumberValue
+}
+
+// NumberValue returns the current value if IsNumber() is true, 0 otherwise
+func (value *OneOfNumberNull) NumberValue() float64 {
+	if value.currentType == jsonNumberValue {
+		return value.numberValue
+	}
+	return 0
+}
+
+// MarshalJSON serialize to json
+func (value OneOfNumberNull) MarshalJSON() ([]byte, error) {
+	switch value.currentType {
+	case jsonInvalidValue:
+		return jsonNullValue, nil
+	case jsonNilValue:
+		return jsonNullValue, nil
+	case jsonNumberValue:
+		return json.Marshal(value.numberValue)
+	}
+	return nil, fmt.Errorf(
+		"OneOfNumberNull unsupported type: %s",
+		value.currentType)
+}
+
+// UnmarshalJSON unserialize a OneOfNumberNull from json
+func (value *OneOfNumberNull) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, jsonNullValue) {
+		value.currentType = jsonNilValue
+	} else {
+		if err := json.Unmarshal(data, &value.numberValue); err != nil {
+			return err
+		}
+		value.currentType = jsonNumberValue
+	}
+	return nil
+}
+
+// OneOfBoolNull is a 'bool' or a 'null', and can be emptied
+type OneOfBoolNull struct {
+	currentType jsonValueType
+	boolValue   bool
+}
+
+// NewOneOfBoolNull creates a empty OneOfBoolNull
+func NewOneOfBoolNull() OneOfBoolNull {
+	return OneOfBoolNull{jsonInvalidValue, false}
+}
+
+// NewOneOfBoolNullBool creates a OneOfBoolNull of type number
+func NewOneOfBoolNullBool(value bool) OneOfBoolNull {
+	return OneOfBoolNull{jsonBoolValue, value}
+}
+
+// NewOneOfBoolNullNull creates a OneOfBoolNull of type null
+func NewOneOfBoolNullNull() OneOfBoolNull {
+	return OneOfBoolNull{jsonNilValue, false}
+}
+
+// IsEmpty returns true if the value is empty
+func (value *OneOfBoolNull) IsEmpty() bool {
+	return value.currentType == jsonInvalidValue
+}
+
+// IsNull returns true if the value is 'null'
+func (value *OneOfBoolNull) IsNull() bool {
+	return value.currentType == jsonNilValue
+}
+
+// IsBool returns true if the value is a bool
+func (value *OneOfBoolNull) IsBool() bool {
+	return value.currentType == jsonBoolValue
+}
+
+// BoolValue returns the current value if IsBool() is true, false otherwise
+func (value *OneOfBoolNull) BoolValue() bool {
+	if value.currentType == jsonBoolValue {
+		return value.boolValue
+	}
+	return false
+}
+
+// MarshalJSON serialize to json
+func (value OneOfBoolNull) MarshalJSON() ([]byte, error) {
+	switch value.currentType {
+	case jsonInvalidValue:
+		return jsonNullValue, nil
+	case jsonNilValue:
+		return jsonNullValue, nil
+	case jsonBoolValue:
+		return json.Marshal(value.boolValue)
+	}
+	return nil, fmt.Errorf(
+		"OneOfBoolNull unsupported type: %s",
+		value.currentType)
+}
+
+// UnmarshalJSON unserialize a OneOfBoolNull from json
+func (value *OneOfBoolNull) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, jsonNullValue) {
+		value.currentType = jsonNilValue
+	} else {
+		if err := json.Unmarshal(data, &value.boolValue); err != nil {
+			return err
+		}
+		value.currentType = jsonBoolValue
+	}
+	return nil
+}
+{{- end }}
+{{- end }}
+
+{{- /* the JSON marshalling methods of a oneOf */ -}}
+{{- define "oneOfJSON" }}
+{{- $top := . }}
+{{- with $oneOf := .OneOf }}
+
+func (o {{ $oneOf.Name }}) MarshalJSON() ([]byte, error) {
+	{{- range .Types }}
+	{{- if eq "nil" .Type }}
+	if o.Type == {{ $oneOf.Name }}Enum{{ .ShortType }} {
+		return jsonNullValue, nil
+	}
+	{{- end }}
+	{{- end }}
+	return {{ $top.Pkg "encoding/json" }}.Marshal(o.value)
+}
+
+func (o *{{ $oneOf.Name }}) UnmarshalJSON(data []byte) error {
+	switch whatIsNext(data) {
+
+	{{- range .Types }}
+	{{- if eq "nil" .Type }}
+	case jsonNilValue:
+		o.Set{{ .ShortType }}()
+	{{- else if or (eq "bool" .Type) (eq "string" .Type) }}
+	case json{{ jsoniterValueType .JSONType }}:
+		var v {{ .Type }}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		o.Set{{ .ShortType }}(v)
+	{{- end }}
+	{{- end }}
+
+	{{- if and (oneOfContainsJsonType . "integer") (oneOfContainsJsonType . "number") }}
+	case jsonNumberValue:
+		{{- with .GetByJSONType "integer" }}
+		var i {{ .Type }}
+		if err := json.Unmarshal(data, &i); err == nil {
+			o.Set{{ .ShortType }}(i)
+			return nil
+		}
+		{{- end }}
+		{{- with .GetByJSONType "number" }}
+
+		var f {{ .Type }}
+		if err := json.Unmarshal(data, &f); err != nil {
+			return err
+		}
+		o.Set{{ .ShortType }}(f)
+		{{- end }}
+	{{- else if oneOfContainsJsonType . "integer" }}
+	{{- with .GetByJSONType "integer" }}
+	case jsonNumberValue:
+		var v {{ .Type }}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		o.Set{{ .ShortType }}(v)
+	{{- end }}
+	{{- else if oneOfContainsJsonType . "number" }}
+	{{- with .GetByJSONType "number" }}
+	case jsonNumberValue:
+		var v {{ .Type }}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		o.Set{{ .ShortType }}(v)
+	{{- end }}
+	{{- end }}
+	{{- if oneOfContainsJsonType . "array" }}
+	{{- with .GetByJSONType "array" }}
+	case jsonArrayValue:
+		var v {{ .Type }}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		o.Set{{ .ShortType }}(v)
+	{{- end }}
+	{{- end }}
+	{{- if oneOfContainsJsonType . "object" }}
+	case jsonObjectValue:
+		{{- if .Discriminator }}
+		var discriminator struct {
+			Value string {{ $top.Backquote }}json:{{ printf "%q" .Discriminator }}{{ $top.Backquote }}
+		}
+		if err := json.Unmarshal(data, &discriminator); err != nil {
+			return err
+		}
+
+		switch discriminator.Value {
+		{{- range .Types }}
+		{{- if .DiscriminatorValues }}
+		case {{ range $i, $v := .DiscriminatorValues }}{{ if $i }}, {{ end }}{{ printf "%q" $v }}{{ end }}:
+			var value {{ deferedType .Type }}
+			if err := json.Unmarshal(data, &value); err != nil {
+				return err
+			}
+			o.Set{{ .ShortType }}({{ if ispointer .Type }}&{{ end }}value)
+		{{- end }}
+		{{- end }}
+		default:
+			return {{ $top.Pkg "fmt" }}.Errorf({{ printf "%s: unexpected %s: %%q" $oneOf.Name .Discriminator | printf "%q" }}, discriminator.Value)
+		}
+		{{- else }}
+		var lastError error
+
+		{{- range .Types }}
+		{{- if eq "object" .JSONType }}
+
+		{ // attempt to read a {{ .Type }}
+			var value {{ deferedType .Type }}
+			if lastError = json.Unmarshal(data, &value); lastError == nil {
+				o.Set{{ .ShortType }}({{ if ispointer .Type }}&{{ end }}value)
+				return nil
+			}
+		}
+		{{- end }}
+		{{- end }}
+
+		return lastError
+		{{- end }}
+	{{- end }}
+	}
+	return nil
+}
+
+{{- end }}
+{{- end }}
+
+{{- /* the JSON marshalling methods of an anyOf */ -}}
+{{- define "anyOfJSON" }}
+{{- $top := . }}
+{{- with $anyOf := .AnyOf }}
+
+func (o {{ $anyOf.Name }}) MarshalJSON() ([]byte, error) {
+	{{- range .Types }}
+	{{- if eq "nil" .Type }}
+	if o.Type == {{ $anyOf.Name }}Enum{{ .ShortType }} {
+		return jsonNullValue, nil
+	}
+	{{- end }}
+	{{- end }}
+	return {{ $top.Pkg "encoding/json" }}.Marshal(o.value)
+}
+
+// UnmarshalJSON decodes the value as the first type that accepts it
+{{- if $top.AnyOfAllMatches }}, and
+// records all the types accepting it in Matches
+{{- end }}
+func (o *{{ $anyOf.Name }}) UnmarshalJSON(data []byte) error {
+	next := whatIsNext(data)
+
+	*o = {{ $anyOf.Name }}{}
+	var lastError error
+
+	{{- range .Types }}
+	{{- if eq "nil" .Type }}
+
+	if next == jsonNilValue {
+		{{- if $top.AnyOfAllMatches }}
+		if o.IsNotSet() {
+			o.SetNil()
+		}
+		o.Matches = append(o.Matches, {{ $anyOf.Name }}Enum{{ .ShortType }})
+		{{- else }}
+		o.SetNil()
+		return nil
+		{{- end }}
+	}
+	{{- else }}
+
+	{{ with jsoniterValueType .JSONType }}if next == json{{ . }} {{ end }}{ // attempt to read a {{ .Type }}
+		var value {{ deferedType .Type }}
+		if err := json.Unmarshal(data, &value); err == nil {
+			{{- if $top.AnyOfAllMatches }}
+			if o.IsNotSet() {
+				o.Set{{ .ShortType }}({{ if ispointer .Type }}&{{ end }}value)
+			}
+			o.Matches = append(o.Matches, {{ $anyOf.Name }}Enum{{ .ShortType }})
+			{{- else }}
+			o.Set{{ .ShortType }}({{ if ispointer .Type }}&{{ end }}value)
+			return nil
+			{{- end }}
+		} else {
+			lastError = err
+		}
+	}
+	{{- end }}
+	{{- end }}
+
+	if o.IsNotSet() {
+		if lastError == nil {
+			lastError = {{ $top.Pkg "fmt" }}.Errorf("unexpected value type: %s", next)
+		}
+		return fmt.Errorf("{{ $anyOf.Name }}: %v", lastError)
+	}
+	return nil
+}
+
+{{- end }}
+{{- end }}
+
+{{- /* the JSON unmarshalling methods of an enum */ -}}
+{{- define "enumJSON" }}
+{{- $top := . }}
+{{- with $enum := .Enum }}
+{{- if .Mixed }}
+
+// MarshalJSON serializes to JSON
+func (e {{ .Name }}) MarshalJSON() ([]byte, error) {
+	if e == "" {
+		return jsonNullValue, nil
+	}
+	return []byte(e), nil
+}
+
+// UnmarshalJSON unserializes a {{ .Name }} from JSON, rejecting unknown values
+func (e *{{ .Name }}) UnmarshalJSON(data []byte) error {
+	buf := {{ $top.Pkg "bytes" }}.NewBuffer(nil)
+	if err := {{ $top.Pkg "encoding/json" }}.Compact(buf, data); err != nil {
+		return err
+	}
+	value := {{ .Name }}(buf.String())
+	if !value.IsValid() {
+		return {{ $top.Pkg "fmt" }}.Errorf("unexpected {{ .Name }} value: %s", value)
+	}
+	*e = value
+	return nil
+}
+{{- else }}
+
+// UnmarshalJSON unserializes a {{ .Name }} from JSON, rejecting unknown values
+func (e *{{ .Name }}) UnmarshalJSON(data []byte) error {
+	var value {{ .Type }}
+	if err := {{ $top.Pkg "encoding/json" }}.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if !{{ .Name }}(value).IsValid() {
+		return {{ $top.Pkg "fmt" }}.Errorf("unexpected {{ .Name }} value: %v", value)
+	}
+	*e = {{ .Name }}(value)
+	return nil
+}
+{{- end }}
+
+{{- end }}
+{{- end }}
+
+{{- /* the JSON marshalling methods of a struct */ -}}
+{{- define "marshal" }}
+{{- $top := . }}
+{{- with $struct := .Struct }}
+
+// MarshalJSON serializes to JSON
+func (s {{ .Name }}) MarshalJSON() ([]byte, error) {
+	{{- if .NoProp }}
+	return []byte("{}"), nil
+	{{- else }}
+	w := objectWriter{}
+
+	{{- range .Fields }}
+	{{- if ne .JSONName "-" }}
+
+	// Marshal the {{ .Name }} field
+	{{- if and .Required .IsPointer }}
+
+	// {{ .Name }} is required
+	if s.{{ .Name }} == nil {
+		return nil, {{ $top.Pkg "errors" }}.New("{{ .Name }} ({{ .JSONName }}) is a required")
+	}
+	{{- end }}
+	{{- if not .Required }}
+	if !IsEmpty(s.{{ .Name }}) {
+	{{- end }}
+	if err := w.Field("{{ .JSONName }}", s.{{ .Name }}{{ if eq .Type "*url.URL" }}.String(){{ end }}); err != nil {
+		return nil, err
+	}
+	{{- if not .Required }}
+	}
+	{{- end}}
+
+	{{- end}}
+	{{- end}}
+	{{- range .PatternProperties }}
+	for key, value := range s.{{ .Name }} {
+		if err := w.Field(key, value); err != nil {
+			return nil, err
+		}
+	}
+	{{- end }}
+	{{- if and .AdditionalType (ne .AdditionalType "false")}}
+	for key, value := range s.AdditionalProperties {
+		if err := w.Field(key, value); err != nil {
+			return nil, err
+		}
+	}
+	{{- end}}
+	return w.Bytes(), nil
+	{{- end}}
+}
+
+{{- end }}
+{{- end }}
+
+{{- /* the JSON unmarshalling methods of a struct */ -}}
+{{- define "unmarshal" }}
+{{- $top := . }}
+{{- with $struct := .Struct }}
+
+func (s *{{ .Name }}) UnmarshalJSON(data []byte) error {
+	{{- range .Fields }}
+	{{- if .Required}}
+	{{ .Name }}Received := false
+	{{- end}}
+	{{- end}}
+
+	err := readObject(data, func(field string, value {{ $top.Pkg "encoding/json" }}.RawMessage) error {
+		switch field {
+		{{- range .Fields }}
+		{{- if ne .JSONName "-" }}
+		case "{{ .JSONName }}":
+			{{- if and $top.AlwaysAcceptFalse (ne .Type "bool") (ne .Type "OneOfBoolNull") (ne .Type "Nullable[bool]")}}
+			if whatIsNext(value) == jsonBoolValue {
+				if string(value) == "true" {
+					return {{ $top.Pkg "errors" }}.New("reading field {{ .JSONName }}: {{ .JSONName }} is 'true', but the expected type is {{ .Type }}")
+				}
+				// received 'false', which we accept and ignore for now
+				return nil
+			}
+			{{- end}}
+			{{- if eq .Type "*url.URL" }}
+			var v string
+			if err := json.Unmarshal(value, &v); err != nil {
+				return err
+			}
+			u, err := url.Parse(v)
+			if err != nil {
+				return {{ $top.Pkg "fmt" }}.Errorf("reading field {{ .JSONName }}: %v", err)
+			}
+			s.{{ .Name }} = u
+			{{- else }}
+			if err := json.Unmarshal(value, &s.{{ .Name }}); err != nil {
+				return err
+			}
+			{{- if and (eq .Type "string") (eq 1 (len .Enum)) }}
+			if s.{{ .Name }} != {{ index .Enum 0 }} {
+				return {{ $top.Pkg "fmt" }}.Errorf("{{ .JSONName }}: Expected %s, got \"%s\"", {{ index .Enum 0 }}, s.{{ .Name }})
+			}
+			{{- end }}
+			{{- end }}
+			{{- if .Required}}
+			{{ .Name }}Received = true
+			{{- end}}
+		{{- end}}
+		{{- end}}
+		default:
+			{{- range .PatternProperties }}
+			if matchPattern({{ printf "%q" .Pattern }}, field) {
+				if s.{{ .Name }} == nil {
+					s.{{ .Name }} = make(map[string]{{ .Type }})
+				}
+				var patternValue {{ .Type }}
+				if err := json.Unmarshal(value, &patternValue); err != nil {
+					return err
+				}
+				s.{{ .Name }}[field] = patternValue
+				return nil
+			}
+			{{- end }}
+			{{- if eq .AdditionalType "false" }}
+			return {{ $top.Pkg "errors" }}.New("reading {{ .Name }}: additional property not allowed: \"" + field + "\"")
+			{{- else if .AdditionalType }}
+			if s.AdditionalProperties == nil {
+				s.AdditionalProperties = make(map[string]{{ .AdditionalType }}, 0)
+			}
+			var additionalValue {{ .AdditionalType }}
+			if err := json.Unmarshal(value, &additionalValue); err != nil {
+				return err
+			}
+			s.AdditionalProperties[field] = additionalValue
+			{{- else }}
+			// Ignore the additional property
+			{{- end }}
+		}
+		{{- if not (and .NoProp (eq .AdditionalType "false")) }}
+		return nil
+		{{- end }}
+	})
+	if err != nil {
+		return err
+	}
+
+	{{- range .Fields }}
+	{{- if .Required}}
+
+	if !{{ .Name }}Received {
+		return {{ $top.Pkg "errors" }}.New("validating {{ $struct.Name }}: \"{{ .JSONName }}\" is required but was not present")
+	}
+	{{- end}}
+	{{- end}}
+	return nil
+}
+
+{{- end }}
+{{- end }}
+
+{{- /* the JSON array marshalling methods of a tuple struct */ -}}
+{{- define "tupleMarshal" }}
+{{- $top := . }}
+{{- with $struct := .Struct }}
+
+// MarshalJSON serializes to a JSON array
+func (s {{ .Name }}) MarshalJSON() ([]byte, error) {
+	items := []interface{}{
+		{{- range .TupleItems }}
+		s.{{ .Name }},
+		{{- end }}
+	}
+	{{- if and .AdditionalItems (ne .AdditionalItems "false") }}
+	for _, item := range s.AdditionalItems {
+		items = append(items, item)
+	}
+	{{- end }}
+	return {{ $top.Pkg "encoding/json" }}.Marshal(items)
+}
+
+{{- end }}
+{{- end }}
+
+{{- /* the JSON array unmarshalling methods of a tuple struct */ -}}
+{{- define "tupleUnmarshal" }}
+{{- $top := . }}
+{{- with $struct := .Struct }}
+
+func (s *{{ .Name }}) UnmarshalJSON(data []byte) error {
+	var items []{{ $top.Pkg "encoding/json" }}.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	for i, item := range items {
+		var err error
+		switch i {
+		{{- range $i, $f := .TupleItems }}
+		case {{ $i }}:
+			err = json.Unmarshal(item, &s.{{ .Name }})
+		{{- end }}
+		default:
+			{{- if eq .AdditionalItems "false" }}
+			return {{ $top.Pkg "fmt" }}.Errorf("reading {{ .Name }}: additional item not allowed at %d", i)
+			{{- else if .AdditionalItems }}
+			var v {{ .AdditionalItems }}
+			err = json.Unmarshal(item, &v)
+			s.AdditionalItems = append(s.AdditionalItems, v)
+			{{- else }}
+			// Ignore the additional item
+			{{- end }}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	{{- with .TupleMinItems }}
+
+	if len(items) < {{ . }} {
+		return {{ $top.Pkg "fmt" }}.Errorf("validating {{ $struct.Name }}: expected at least {{ . }} items, got %d", len(items))
+	}
+	{{- end }}
+	return nil
+}
+
+{{- end }}
+{{- end }}
+`
@@ -1,7 +1,13 @@
 package generate
 
 import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
+	"sort"
 	"strings"
 	"testing"
 )
@@ -89,3 +95,298 @@ func TestLineAndCharacterFromOffset(t *testing.T) {
 		}
 	}
 }
+
+func TestOutputWithOptions(t *testing.T) {
+	root := &Schema{
+		Title:     "Link",
+		TypeValue: "object",
+		Properties: map[string]*Schema{
+			"href": {TypeValue: "string", Format: "uri"},
+		},
+	}
+	root.Init()
+	g := New(root)
+	if err := g.CreateTypes(); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	err := OutputWithOptions(&buf, g, OutputOptions{
+		PackageName:   "links",
+		Header:        "Copyright the authors.\n\nLicensed under the MIT license.\n",
+		BuildTags:     "linux && !appengine",
+		ImportAliases: map[string]string{"net/url": "neturl"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := buf.String()
+	for _, expected := range []string{
+		"// Copyright the authors.\n//\n// Licensed under the MIT license.\n",
+		"//go:build linux && !appengine\n// +build linux,!appengine\n\npackage links\n",
+		`neturl "net/url"`,
+		"Href *neturl.URL",
+	} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("Expected the output to contain %q", expected)
+		}
+	}
+
+	if err := OutputWithOptions(&buf, g, OutputOptions{PackageName: "links", BuildTags: "linux &&"}); err == nil {
+		t.Error("Expected an error for invalid build tags")
+	}
+}
+
+func TestOutput(t *testing.T) {
+	root := &Schema{
+		Title:      "Link",
+		TypeValue:  "object",
+		Properties: map[string]*Schema{"href": {TypeValue: "string"}},
+	}
+	root.Init()
+	g := New(root)
+	if err := g.CreateTypes(); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	Output(&buf, g, "links", false, false)
+	if code := buf.String(); !strings.Contains(code, "package links\n") || !strings.Contains(code, "type Link struct") {
+		t.Errorf("Unexpected output %s", code)
+	}
+}
+
+func TestFormatCode(t *testing.T) {
+	code := `package p
+
+import (
+	"strings"
+	"fmt"
+)
+
+func f( ) string {
+      return fmt.Sprint(1)
+}
+`
+	formatted, err := formatCode([]byte(code), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `package p
+
+import (
+	"fmt"
+)
+
+func f() string {
+	return fmt.Sprint(1)
+}
+`
+	if string(formatted) != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, formatted)
+	}
+
+	formatted, err = formatCode([]byte("package p\n\nimport (\n\t\"fmt\"\n)\n\ntype T struct{}\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "package p\n\ntype T struct{}\n"; string(formatted) != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, formatted)
+	}
+
+	_, err = formatCode([]byte("package p\n\nfunc f() {\n\treturn 1 +\n}\n"), nil)
+	if err == nil || !strings.Contains(err.Error(), ">    5: }") {
+		t.Errorf("Expected a syntax error with the offending line, got %v", err)
+	}
+}
+
+func TestOutputFileNames(t *testing.T) {
+	tests := map[string]string{
+		"OrderLine":   "order_line.go",
+		"HTTPServer":  "httpserver.go",
+		"Address2Go":  "address2_go.go",
+		"my-schema":   "my_schema.go",
+		"helpers":     "helpers_types.go",
+		"Integration": "integration.go",
+		"OrderTest":   "order_test_types.go",
+	}
+	for name, expected := range tests {
+		if actual := goFileName(name); actual != expected {
+			t.Errorf("expected %s for %s, got %s", expected, name, actual)
+		}
+	}
+	if name := documentName("file:///schemas/address.schema.json"); name != "address" {
+		t.Errorf("expected address, got %s", name)
+	}
+	if name := documentName("urn:example:customer"); name != "customer" {
+		t.Errorf("expected customer, got %s", name)
+	}
+}
+
+func TestOutputFilesPerType(t *testing.T) {
+	root := &Schema{
+		Title:     "Order",
+		TypeValue: "object",
+		Properties: map[string]*Schema{
+			"line":   {TypeValue: "object", Properties: map[string]*Schema{"count": {TypeValue: "integer"}}},
+			"status": {TypeValue: "string", Enum: []json.RawMessage{[]byte(`"open"`), []byte(`"closed"`)}},
+		},
+	}
+	root.Init()
+	g := New(root)
+	if err := g.CreateTypes(); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := OutputFiles(g, OutputOptions{PackageName: "orders"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if !reflect.DeepEqual(names, []string{"helpers.go", "line.go", "order.go", "status.go"}) {
+		t.Errorf("unexpected files %v", names)
+	}
+	if !strings.Contains(string(files["status.go"]), "type Status string") {
+		t.Errorf("expected status.go to contain the Status enum:\n%s", files["status.go"])
+	}
+	if strings.Contains(string(files["order.go"]), "func IsEmpty(") {
+		t.Error("expected the helpers to be in helpers.go only")
+	}
+}
+
+func TestOutputWithoutHelpers(t *testing.T) {
+	root := &Schema{
+		Title:      "Item",
+		TypeValue:  "object",
+		Properties: map[string]*Schema{"name": {TypeValue: "string"}},
+		Required:   []string{"name"},
+	}
+	root.Init()
+	g := New(root)
+	if err := g.CreateTypes(); err != nil {
+		t.Fatal(err)
+	}
+
+	var types, helpers bytes.Buffer
+	if err := OutputWithOptions(&types, g, OutputOptions{PackageName: "items", NoHelpers: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := OutputHelpers(&helpers, OutputOptions{PackageName: "items", Helpers: []string{"UUID"}}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(types.String(), "func IsEmpty(") || strings.Contains(types.String(), "type commaTracker") {
+		t.Error("Expected no helpers in the types")
+	}
+	if !strings.Contains(types.String(), "type Item struct") {
+		t.Error("Expected the Item type")
+	}
+	for _, expected := range []string{"func IsEmpty(", "type commaTracker", "type UUID [16]byte", "type EmptyString struct"} {
+		if !strings.Contains(helpers.String(), expected) {
+			t.Errorf("Expected the helpers to contain %q", expected)
+		}
+	}
+	if strings.Contains(helpers.String(), "type Nullable[") {
+		t.Error("Expected no Nullable helper unless requested")
+	}
+	if err := OutputHelpers(&helpers, OutputOptions{PackageName: "items", Helpers: []string{"Optional"}}); err == nil {
+		t.Error("Expected an error for an unknown helper")
+	}
+}
+
+func TestOutputWithTemplateDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "templates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	root := &Schema{
+		Title:      "Item",
+		TypeValue:  "object",
+		Properties: map[string]*Schema{"name": {TypeValue: "string"}},
+	}
+	root.Init()
+	g := New(root)
+	if err := g.CreateTypes(); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	err = OutputWithOptions(&buf, g, OutputOptions{PackageName: "items", TemplateDir: dir})
+	if err == nil || !strings.Contains(err.Error(), "no template") {
+		t.Errorf("Expected an error for a directory without templates, got %v", err)
+	}
+
+	extra := `{{ define "structExtra" }}
+func (s *{{ .Struct.Name }}) Kind() string { return "item" }
+{{ end }}`
+	if err := ioutil.WriteFile(filepath.Join(dir, "extra.tmpl"), []byte(extra), 0644); err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if err := OutputWithOptions(&buf, g, OutputOptions{PackageName: "items", TemplateDir: dir}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `func (s *Item) Kind() string { return "item" }`) {
+		t.Errorf("Expected the structExtra template to be used, got:\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "type Item struct") {
+		t.Error("Expected the other templates to be kept")
+	}
+}
+
+func TestOutputStdJSON(t *testing.T) {
+	root := &Schema{
+		Title:      "Item",
+		TypeValue:  "object",
+		Properties: map[string]*Schema{"name": {TypeValue: "string"}},
+		Required:   []string{"name"},
+	}
+	root.Init()
+	g := New(root)
+	if err := g.CreateTypes(); err != nil {
+		t.Fatal(err)
+	}
+
+	var types, helpers bytes.Buffer
+	if err := OutputWithOptions(&types, g, OutputOptions{PackageName: "items", StdJSON: true, NoHelpers: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := OutputHelpers(&helpers, OutputOptions{PackageName: "items", StdJSON: true, Helpers: []string{"Nullable", "UUID"}}); err != nil {
+		t.Fatal(err)
+	}
+	for _, code := range []string{types.String(), helpers.String()} {
+		if strings.Contains(code, "jsoniter") {
+			t.Errorf("Expected no jsoniter in:\n%s", code)
+		}
+	}
+	if !strings.Contains(types.String(), "func (s Item) MarshalJSON() ([]byte, error)") {
+		t.Error("Expected the Item MarshalJSON method")
+	}
+	if !strings.Contains(helpers.String(), "func readObject(") {
+		t.Error("Expected the encoding/json helpers")
+	}
+}
+
+func TestUseType(t *testing.T) {
+	tests := map[string]bool{
+		"UUID":                  true,
+		"[]*UUID":               true,
+		"Nullable[UUID]":        true,
+		"map[string]UUID":       true,
+		"uuid.UUID":             false,
+		"UUIDs":                 false,
+		"Nullable[RequestUUID]": false,
+	}
+	for typ, expected := range tests {
+		d := &OutputData{ImportPaths: make(map[string]string)}
+		d.useType(typ)
+		if d.UUID != expected {
+			t.Errorf("Expected the UUID helper to be used by %s: %v", typ, expected)
+		}
+	}
+}
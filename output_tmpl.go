@@ -68,6 +68,27 @@ var funcs = template.FuncMap{
 		}
 		return false
 	},
+	// jsoniterValueType returns the jsoniter ValueType matching a json type, or
+	// an empty string if the json type is unknown
+	"jsoniterValueType": func(t string) string {
+		switch t {
+		case "string":
+			return "StringValue"
+		case "number", "integer":
+			return "NumberValue"
+		case "boolean":
+			return "BoolValue"
+		case "null":
+			return "NilValue"
+		case "array":
+			return "ArrayValue"
+		case "object":
+			return "ObjectValue"
+		}
+		return ""
+	},
+	// jsonPointer escapes a JSON pointer reference token
+	"jsonPointer": jsonPointerEscape,
 	// ispointer returns true if the given type starts with "*"
 	"ispointer": func(t string) bool {
 		return t[0] == '*'
@@ -94,7 +115,14 @@ var funcs = template.FuncMap{
 
 var headerTmpl = template.Must(template.New("schema-generate").Funcs(funcs).Parse(
 	`// Code generated by schema-generate. DO NOT EDIT.
-
+{{ if .Header }}
+{{ range .Header }}//{{ if . }} {{ . }}{{ end }}
+{{ end }}
+{{- end }}
+{{- if .BuildConstraints }}
+{{ range .BuildConstraints }}{{ . }}
+{{ end }}
+{{- end }}
 package {{.PackageName}}
 
 import (
@@ -106,8 +134,273 @@ import (
 `))
 
 var mainTmpl = template.Must(template.New("schema-generate").Funcs(funcs).Parse(
+	`{{- template "helpers" . }}
+{{ template "types" . }}`))
+
+// helpersTmpl generates the runtime helpers used by the types
+var helpersTmpl = template.Must(mainTmpl.New("helpers").Parse(
 	`{{- with $top := . -}}
 
+type isEmptyChecker interface {
+	IsEmpty() bool
+}
+
+// IsEmpty reports whether v is zero struct
+// Does not support cycle pointers for performance, so as json
+func IsEmpty(v interface{}) bool {
+	if i, ok := v.(isEmptyChecker); ok {
+		return i.IsEmpty()
+	}
+	rv := {{ .Pkg "reflect" }}.ValueOf(v)
+	return !rv.IsValid() || rv.IsZero()
+}
+
+var (
+	jsonNullValue = []byte("null")
+)
+
+// ValidationError is a violation of a schema validation keyword
+type ValidationError struct {
+	// Path is the JSON pointer of the invalid value
+	Path    string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// ValidationErrors are all the violations found in a value
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return {{ .Pkg "strings" }}.Join(messages, "; ")
+}
+
+type pathValidator interface {
+	ValidatePath(path string) ValidationErrors
+}
+
+// validateValue validates the generated types found in v
+func validateValue(path string, v interface{}) ValidationErrors {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		if rv.IsNil() {
+			return nil
+		}
+	}
+	if validator, ok := v.(pathValidator); ok {
+		return validator.ValidatePath(path)
+	}
+	var errs ValidationErrors
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			errs = append(errs, validateValue(path+"/"+{{ .Pkg "strconv" }}.Itoa(i), rv.Index(i).Interface())...)
+		}
+	case reflect.Map:
+		keys := rv.MapKeys()
+		{{ .Pkg "sort" }}.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, key := range keys {
+			token := strings.Replace(strings.Replace(key.String(), "~", "~0", -1), "/", "~1", -1)
+			errs = append(errs, validateValue(path+"/"+token, rv.MapIndex(key).Interface())...)
+		}
+	}
+	return errs
+}
+
+var patterns {{ .Pkg "sync" }}.Map
+
+// matchPattern returns true if value matches the regular expression pattern
+func matchPattern(pattern string, value string) bool {
+	re, ok := patterns.Load(pattern)
+	if !ok {
+		re, _ = patterns.LoadOrStore(pattern, {{ .Pkg "regexp" }}.MustCompile(pattern))
+	}
+	return re.(*regexp.Regexp).MatchString(value)
+}
+
+// hasUniqueItems returns true if the items of the slice v are all different
+func hasUniqueItems(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	for i := 0; i < rv.Len(); i++ {
+		for j := i + 1; j < rv.Len(); j++ {
+			if reflect.DeepEqual(rv.Index(i).Interface(), rv.Index(j).Interface()) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+{{- template "jsonHelpers" . }}
+
+{{- if .Nullable }}
+
+type nullableState int
+
+const (
+	nullableAbsent nullableState = iota
+	nullableNull
+	nullableSet
+)
+
+// Nullable is a value that may be absent, null or set
+type Nullable[T any] struct {
+	value T
+	state nullableState
+}
+
+// NewNullable returns a Nullable set to value
+func NewNullable[T any](value T) Nullable[T] {
+	return Nullable[T]{value: value, state: nullableSet}
+}
+
+// NewNull returns a null Nullable
+func NewNull[T any]() Nullable[T] {
+	return Nullable[T]{state: nullableNull}
+}
+
+// IsEmpty returns true if the value is absent
+func (n Nullable[T]) IsEmpty() bool {
+	return n.state == nullableAbsent
+}
+
+// IsNull returns true if the value is null
+func (n Nullable[T]) IsNull() bool {
+	return n.state == nullableNull
+}
+
+// IsSet returns true if the value is neither absent nor null
+func (n Nullable[T]) IsSet() bool {
+	return n.state == nullableSet
+}
+
+// Value returns the value, the zero value if it is not set
+func (n Nullable[T]) Value() T {
+	return n.value
+}
+
+// Get returns the value and true if it is set
+func (n Nullable[T]) Get() (T, bool) {
+	return n.value, n.state == nullableSet
+}
+
+// Set sets the value
+func (n *Nullable[T]) Set(value T) {
+	n.value = value
+	n.state = nullableSet
+}
+
+// SetNull sets the value to null
+func (n *Nullable[T]) SetNull() {
+	var zero T
+	n.value = zero
+	n.state = nullableNull
+}
+
+// Unset makes the value absent
+func (n *Nullable[T]) Unset() {
+	var zero T
+	n.value = zero
+	n.state = nullableAbsent
+}
+
+// MarshalJSON serializes the value, null if it is not set
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if n.state != nullableSet {
+		return jsonNullValue, nil
+	}
+	// the generated types marshal with pointer receivers
+	return {{ .Pkg "encoding/json" }}.Marshal(&n.value)
+}
+
+// UnmarshalJSON deserializes the value, or null
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	if string({{ .Pkg "bytes" }}.TrimSpace(data)) == "null" {
+		n.SetNull()
+		return nil
+	}
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	n.Set(value)
+	return nil
+}
+
+// ValidatePath validates the value if it is set
+func (n Nullable[T]) ValidatePath(path string) ValidationErrors {
+	if n.state != nullableSet {
+		return nil
+	}
+	if validator, ok := interface{}(&n.value).(pathValidator); ok {
+		return validator.ValidatePath(path)
+	}
+	return validateValue(path, n.value)
+}
+{{- end }}
+
+{{- if .UUID }}
+
+// UUID is a RFC 4122 UUID
+type UUID [16]byte
+
+// ParseUUID parses a UUID from its canonical textual representation
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return u, fmt.Errorf("invalid UUID: %q", s)
+	}
+	b, err := {{ .Pkg "encoding/hex" }}.DecodeString(s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:])
+	if err != nil {
+		return u, fmt.Errorf("invalid UUID: %q", s)
+	}
+	copy(u[:], b)
+	return u, nil
+}
+
+// String returns the canonical textual representation of the UUID
+func (u UUID) String() string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
+
+// MarshalText serializes the UUID to its canonical textual representation
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText parses a UUID from its canonical textual representation
+func (u *UUID) UnmarshalText(text []byte) error {
+	v, err := ParseUUID(string(text))
+	if err != nil {
+		return err
+	}
+	*u = v
+	return nil
+}
+{{- end }}
+{{- end -}}
+
+{{- /* the JSON runtime helpers */ -}}
+{{- define "jsonHelpers" }}
+{{- with $top := . }}
+
 func ValueTypeToString(valueType jsoniter.ValueType) string {
 	switch valueType {
 	case jsoniter.StringValue:
@@ -140,24 +433,6 @@ func (t *commaTracker) More() {
 	}
 }
 
-type isEmptyChecker interface {
-	IsEmpty() bool
-}
-
-// IsEmpty reports whether v is zero struct
-// Does not support cycle pointers for performance, so as json
-func IsEmpty(v interface{}) bool {
-	if i, ok := v.(isEmptyChecker); ok {
-		return i.IsEmpty()
-	}
-	rv := {{ .Pkg "reflect" }}.ValueOf(v)
-	return !rv.IsValid() || rv.IsZero()
-}
-
-var (
-	jsonNullValue = []byte("null")
-)
-
 {{- range $t, $tname := .EmptyTypes }}
 
 // New{{ $tname }} creates a non-empty {{ $tname }}
@@ -475,8 +750,53 @@ func (value *OneOfBoolNull) UnmarshalJSON(data []byte) error {
 	}
 	return nil
 }
+{{- end }}
+{{- end }}
+`))
+
+// typesTmpl generates the types. The named sub-templates are executed with
+// a TemplateData, and can be overridden with the OutputOptions TemplateDir.
+var typesTmpl = template.Must(mainTmpl.New("types").Parse(
+	`{{- with $top := . -}}
+
+{{- range .OneOfs }}
+{{- template "oneOf" ($top.ForOneOf .) }}
+{{- end }}
+
+{{- range .AnyOfs }}
+{{- template "anyOf" ($top.ForAnyOf .) }}
+{{- end }}
 
-{{- range $oneOf := .OneOfs }}
+{{- range .Enums }}
+{{- template "enum" ($top.ForEnum .) }}
+{{- end }}
+
+{{- range .Aliases }}
+{{- template "alias" ($top.ForAlias .) }}
+{{- end }}
+
+{{- range .Structs }}
+{{- template "struct" ($top.ForStruct .) }}
+{{- end }}
+
+{{- range .Structs }}
+{{- template "validate" ($top.ForStruct .) }}
+{{- if .TupleFields }}
+{{- template "tupleMarshal" ($top.ForStruct .) }}
+{{- template "tupleUnmarshal" ($top.ForStruct .) }}
+{{- else if .GenerateCode }}
+{{- template "marshal" ($top.ForStruct .) }}
+{{- template "unmarshal" ($top.ForStruct .) }}
+{{- end }}
+{{- template "structExtra" ($top.ForStruct .) }}
+{{- end }}
+
+{{- end -}}
+
+{{- /* the type of a oneOf schema */ -}}
+{{- define "oneOf" }}
+{{- $top := . }}
+{{- with $oneOf := .OneOf }}
 
 type {{ .Name }}Enum = int
 
@@ -497,13 +817,28 @@ func (o {{ $oneOf.Name }}) IsNotSet() bool {
 	return o.Type == {{ $oneOf.Name }}EnumNotSet
 }
 
+// ValidatePath validates the current value
+func (o {{ $oneOf.Name }}) ValidatePath(path string) ValidationErrors {
+	return validateValue(path, o.value)
+}
+
 {{- range .Types }}
 
 func (o {{ $oneOf.Name }}) Is{{ .ShortType }}() bool {
 	return o.Type == {{ $oneOf.Name }}Enum{{ .ShortType }}
 }
 
-{{- if ne "nil" .Type}}
+{{- if and (eq "String" .ShortType) (eq "string" .Type) }}
+
+// String returns the string value, or formats the value of the other types,
+// the union being a fmt.Stringer
+func (o {{ $oneOf.Name }}) String() string {
+	if v, ok := o.value.(string); ok {
+		return v
+	}
+	return {{ $top.Pkg "fmt" }}.Sprint(o.value)
+}
+{{- else if ne "nil" .Type}}
 func (o {{ $oneOf.Name }}) {{ .ShortType }}() {{ .Type }} {
 	return o.value.({{ .Type }})
 }
@@ -519,10 +854,21 @@ func (o *{{ $oneOf.Name }}) Set{{ .ShortType }}(
 }
 {{- end }}
 
+{{- template "oneOfJSON" $top }}
+
+{{- end }}
+{{- end }}
+
+{{- /* the JSON marshalling methods of a oneOf */ -}}
+{{- define "oneOfJSON" }}
+{{- $top := . }}
+{{- with $oneOf := .OneOf }}
+
 func (o {{ $oneOf.Name }}) MarshalJSON() ([]byte, error) {
 	buf := bytes.NewBuffer(nil)
 	stream := jsoniter.ConfigDefault.BorrowStream(buf)
 	o.MarshalJSONStream(stream)
+	stream.Flush()
 	err := stream.Error
 	jsoniter.ConfigDefault.ReturnStream(stream)
 	if err != nil {
@@ -533,6 +879,9 @@ func (o {{ $oneOf.Name }}) MarshalJSON() ([]byte, error) {
 
 func (o {{ $oneOf.Name }}) MarshalJSONStream(stream *jsoniter.Stream) {
 	switch o.Type {
+	case {{ $oneOf.Name }}EnumNotSet:
+		// the unset value is null, as the nil interface it replaces
+		stream.WriteNil()
 	{{- range .Types }}
 	case {{ $oneOf.Name }}Enum{{ .ShortType }}:
 		{{- if eq "bool" .Type }}
@@ -586,29 +935,60 @@ func (o *{{ $oneOf.Name }}) UnmarshalJSONIterator(iter *jsoniter.Iterator) {
 
 		subiter := jsoniter.ConfigDefault.BorrowIterator(b)
 		defer jsoniter.ConfigDefault.ReturnIterator(subiter)
+		{{- with .GetByJSONType "integer" }}
 
-		i := subiter.ReadInt()
+		var i {{ .Type }}
+		subiter.ReadVal(&i)
 		if subiter.Error == nil || subiter.Error == {{ $top.Pkg "io" }}.EOF {
-			o.SetInt(i)
+			o.Set{{ .ShortType }}(i)
 			return
 		}
 		subiter.Error = nil
+		{{- end }}
+		{{- with .GetByJSONType "number" }}
 
 		subiter.ResetBytes(b)
-		f := subiter.ReadFloat64()
+		var f {{ .Type }}
+		subiter.ReadVal(&f)
 		if subiter.Error == nil || subiter.Error == io.EOF {
-			o.SetFloat64(f)
+			o.Set{{ .ShortType }}(f)
 			return
 		}
+		{{- end }}
 
 		iter.Error = subiter.Error
-		
 	{{- else if oneOfContainsJsonType . "integer" }}
+	{{- with .GetByJSONType "integer" }}
 	case jsoniter.NumberValue:
-		o.SetInt(iter.ReadFloat64())
+		var v {{ .Type }}
+		iter.ReadVal(&v)
+		if iter.Error == {{ $top.Pkg "io" }}.EOF {
+			iter.Error = nil
+		}
+		if iter.Error == nil {
+			o.Set{{ .ShortType }}(v)
+		}
+	{{- end }}
 	{{- else if oneOfContainsJsonType . "number" }}
+	{{- with .GetByJSONType "number" }}
 	case jsoniter.NumberValue:
-		o.SetFloat64(iter.ReadFloat64())
+		var v {{ .Type }}
+		iter.ReadVal(&v)
+		if iter.Error == {{ $top.Pkg "io" }}.EOF {
+			iter.Error = nil
+		}
+		if iter.Error == nil {
+			o.Set{{ .ShortType }}(v)
+		}
+	{{- end }}
+	{{- end }}
+	{{- if oneOfContainsJsonType . "array" }}
+	{{- with .GetByJSONType "array" }}
+	case jsoniter.ArrayValue:
+		var v {{ .Type }}
+		iter.ReadVal(&v)
+		o.Set{{ .ShortType }}(v)
+	{{- end }}
 	{{- end }}
 	{{- if oneOfContainsJsonType . "object" }}
 	case jsoniter.ObjectValue:
@@ -616,6 +996,28 @@ func (o *{{ $oneOf.Name }}) UnmarshalJSONIterator(iter *jsoniter.Iterator) {
 		// the underlying iterator errors, hence makes it impossible to implement
 		// a proper oneOf
 		buf := iter.SkipAndReturnBytes()
+		{{- if .Discriminator }}
+
+		switch discriminator := jsoniter.Get(buf, {{ printf "%q" .Discriminator }}).ToString(); discriminator {
+		{{- range .Types }}
+		{{- if .DiscriminatorValues }}
+		case {{ range $i, $v := .DiscriminatorValues }}{{ if $i }}, {{ end }}{{ printf "%q" $v }}{{ end }}:
+			subIter := jsoniter.ConfigDefault.BorrowIterator(buf)
+			var value {{ deferedType .Type }}
+			subIter.ReadVal(&value)
+			err := subIter.Error
+			jsoniter.ConfigDefault.ReturnIterator(subIter)
+			if err != nil {
+				iter.Error = err
+				return
+			}
+			o.Set{{ .ShortType }}({{ if ispointer .Type }}&{{ end }}value)
+		{{- end }}
+		{{- end }}
+		default:
+			iter.ReportError("{{ $oneOf.Name }}", {{ $top.Pkg "fmt" }}.Sprintf({{ printf "unexpected %s: %%q" .Discriminator | printf "%q" }}, discriminator))
+		}
+		{{- else }}
 
 		var lastError error
 
@@ -637,31 +1039,406 @@ func (o *{{ $oneOf.Name }}) UnmarshalJSONIterator(iter *jsoniter.Iterator) {
 		{{- end }}
 
 		iter.Error = lastError
+		{{- end }}
 	{{- end }}
 	}
 }
+{{- end }}
+{{- end }}
+
+{{- /* the type of an anyOf schema */ -}}
+{{- define "anyOf" }}
+{{- $top := . }}
+{{- with $anyOf := .AnyOf }}
+
+type {{ .Name }}Enum = int
+
+const (
+	{{ $anyOf.Name }}EnumNotSet {{ $anyOf.Name }}Enum = iota
+	{{- range $i, $type := .Types }}
+	{{ $anyOf.Name }}Enum{{ .ShortType }}
+	{{- end }}
+)
+
+// {{ comment .Name .Description }}
+type {{ .Name }} struct {
+	Type {{ $anyOf.Name }}Enum
+	{{- if $top.AnyOfAllMatches }}
+
+	// Matches holds all the types the last decoded value matched
+	Matches []{{ $anyOf.Name }}Enum
+	{{- end }}
 
+	value interface{}
+}
+
+func (o {{ $anyOf.Name }}) IsNotSet() bool {
+	return o.Type == {{ $anyOf.Name }}EnumNotSet
+}
+
+// ValidatePath validates the current value
+func (o {{ $anyOf.Name }}) ValidatePath(path string) ValidationErrors {
+	return validateValue(path, o.value)
+}
+
+{{- range .Types }}
+
+func (o {{ $anyOf.Name }}) Is{{ .ShortType }}() bool {
+	return o.Type == {{ $anyOf.Name }}Enum{{ .ShortType }}
+}
+
+{{- if and (eq "String" .ShortType) (eq "string" .Type) }}
+
+// String returns the string value, or formats the value of the other types,
+// the union being a fmt.Stringer
+func (o {{ $anyOf.Name }}) String() string {
+	if v, ok := o.value.(string); ok {
+		return v
+	}
+	return {{ $top.Pkg "fmt" }}.Sprint(o.value)
+}
+{{- else if ne "nil" .Type}}
+func (o {{ $anyOf.Name }}) {{ .ShortType }}() {{ .Type }} {
+	return o.value.({{ .Type }})
+}
 {{- end }}
 
-{{- range .Aliases }}
+func (o *{{ $anyOf.Name }}) Set{{ .ShortType }}(
+	{{- if ne "nil" .Type}}v {{ .Type }}{{ end -}}
+) {
+	{{- if ne "nil" .Type }}
+	o.value = v
+	{{- else }}
+	o.value = nil
+	{{- end }}
+	o.Type = {{ $anyOf.Name }}Enum{{ .ShortType }}
+	{{- if $top.AnyOfAllMatches }}
+	o.Matches = nil
+	{{- end }}
+}
+{{- end }}
+
+{{- template "anyOfJSON" $top }}
+
+{{- end }}
+{{- end }}
+
+{{- /* the JSON marshalling methods of an anyOf */ -}}
+{{- define "anyOfJSON" }}
+{{- $top := . }}
+{{- with $anyOf := .AnyOf }}
+
+func (o {{ $anyOf.Name }}) MarshalJSON() ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	stream := jsoniter.ConfigDefault.BorrowStream(buf)
+	o.MarshalJSONStream(stream)
+	stream.Flush()
+	err := stream.Error
+	jsoniter.ConfigDefault.ReturnStream(stream)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (o {{ $anyOf.Name }}) MarshalJSONStream(stream *jsoniter.Stream) {
+	switch o.Type {
+	case {{ $anyOf.Name }}EnumNotSet:
+		// the unset value is null, as the nil interface it replaces
+		stream.WriteNil()
+	{{- range .Types }}
+	case {{ $anyOf.Name }}Enum{{ .ShortType }}:
+		{{- if eq "bool" .Type }}
+		stream.WriteBool(o.value.(bool))
+		{{- else if eq "string" .Type }}
+		stream.WriteString(o.value.(string))
+		{{- else if eq "int" .Type }}
+		stream.WriteInt(o.value.(int))
+		{{- else if eq "float64" .Type }}
+		stream.WriteFloat64(o.value.(float64))
+		{{- else if eq "nil" .Type }}
+		stream.WriteNil()
+		{{- else }}
+		stream.WriteVal(o.value)
+		{{- end }}
+	{{- end }}
+	}
+}
+
+func (o *{{ $anyOf.Name }}) UnmarshalJSON(data []byte) error {
+	iter := jsoniter.ConfigDefault.BorrowIterator(data)
+	o.UnmarshalJSONIterator(iter)
+	err := iter.Error
+	jsoniter.ConfigDefault.ReturnIterator(iter)
+	return err
+}
+
+// UnmarshalJSONIterator decodes the value as the first type that accepts it
+{{- if $top.AnyOfAllMatches }}, and
+// records all the types accepting it in Matches
+{{- end }}
+func (o *{{ $anyOf.Name }}) UnmarshalJSONIterator(iter *jsoniter.Iterator) {
+	next := iter.WhatIsNext()
+	buf := iter.SkipAndReturnBytes()
+	if iter.Error == {{ $top.Pkg "io" }}.EOF {
+		iter.Error = nil
+	}
+	if iter.Error != nil {
+		return
+	}
+
+	*o = {{ $anyOf.Name }}{}
+	var lastError error
+
+	{{- range .Types }}
+	{{- if eq "nil" .Type }}
+
+	if next == jsoniter.NilValue {
+		{{- if $top.AnyOfAllMatches }}
+		if o.IsNotSet() {
+			o.SetNil()
+		}
+		o.Matches = append(o.Matches, {{ $anyOf.Name }}Enum{{ .ShortType }})
+		{{- else }}
+		o.SetNil()
+		return
+		{{- end }}
+	}
+	{{- else }}
+
+	{{ with jsoniterValueType .JSONType }}if next == jsoniter.{{ . }} {{ end }}{ // attempt to read a {{ .Type }}
+		subIter := jsoniter.ConfigDefault.BorrowIterator(buf)
+		var value {{ deferedType .Type }}
+		subIter.ReadVal(&value)
+		err := subIter.Error
+		jsoniter.ConfigDefault.ReturnIterator(subIter)
+		if err == nil || err == io.EOF {
+			{{- if $top.AnyOfAllMatches }}
+			if o.IsNotSet() {
+				o.Set{{ .ShortType }}({{ if ispointer .Type }}&{{ end }}value)
+			}
+			o.Matches = append(o.Matches, {{ $anyOf.Name }}Enum{{ .ShortType }})
+			{{- else }}
+			o.Set{{ .ShortType }}({{ if ispointer .Type }}&{{ end }}value)
+			return
+			{{- end }}
+		} else {
+			lastError = err
+		}
+	}
+	{{- end }}
+	{{- end }}
+
+	if o.IsNotSet() {
+		if lastError == nil {
+			lastError = fmt.Errorf("unexpected value type: %s", ValueTypeToString(next))
+		}
+		iter.ReportError("{{ $anyOf.Name }}", lastError.Error())
+	}
+}
+{{- end }}
+{{- end }}
+
+{{- /* the type and constants of an enum */ -}}
+{{- define "enum" }}
+{{- $top := . }}
+{{- with $enum := .Enum }}
+
+// {{ comment .Name .Description }}
+type {{ .Name }} {{ .Type }}
+
+const (
+	{{- range .Values }}
+	{{ .Name }} {{ $enum.Name }} = {{ .Literal }}
+	{{- end }}
+)
+
+// Values returns all the allowed {{ .Name }} values
+func ({{ .Name }}) Values() []{{ .Name }} {
+	return []{{ .Name }}{
+		{{- range .Values }}
+		{{ .Name }},
+		{{- end }}
+	}
+}
+
+// IsValid returns true if the value is one of the allowed {{ .Name }} values
+func (e {{ .Name }}) IsValid() bool {
+	switch e {
+	case {{ range $i, $v := .Values }}{{ if $i }}, {{ end }}{{ .Name }}{{ end }}:
+		return true
+	}
+	return false
+}
+
+// ValidatePath checks the value, if set, is one of the allowed {{ .Name }} values
+func (e {{ .Name }}) ValidatePath(path string) ValidationErrors {
+	if IsEmpty(e) || e.IsValid() {
+		return nil
+	}
+	return ValidationErrors{ValidationError{path, fmt.Sprintf("unexpected value: %v", e)}}
+}
+{{- template "enumJSON" $top }}
+
+{{- end }}
+{{- end }}
+
+{{- /* the JSON unmarshalling methods of an enum */ -}}
+{{- define "enumJSON" }}
+{{- $top := . }}
+{{- with $enum := .Enum }}
+
+{{- if .Mixed }}
+
+// MarshalJSON serializes to JSON
+func (e {{ .Name }}) MarshalJSON() ([]byte, error) {
+	if e == "" {
+		return jsonNullValue, nil
+	}
+	return []byte(e), nil
+}
+
+// UnmarshalJSON unserializes a {{ .Name }} from JSON, rejecting unknown values
+func (e *{{ .Name }}) UnmarshalJSON(data []byte) error {
+	buf := bytes.NewBuffer(nil)
+	if err := {{ $top.Pkg "encoding/json" }}.Compact(buf, data); err != nil {
+		return err
+	}
+	value := {{ .Name }}(buf.String())
+	if !value.IsValid() {
+		return fmt.Errorf("unexpected {{ .Name }} value: %s", value)
+	}
+	*e = value
+	return nil
+}
+
+func (e *{{ .Name }}) UnmarshalJSONIterator(iter *jsoniter.Iterator) {
+	data := iter.SkipAndReturnBytes()
+	if iter.Error != nil && iter.Error != {{ $top.Pkg "io" }}.EOF {
+		return
+	}
+	if err := e.UnmarshalJSON(data); err != nil {
+		iter.ReportError("{{ .Name }}", err.Error())
+	}
+}
+{{- else }}
+
+// UnmarshalJSON unserializes a {{ .Name }} from JSON, rejecting unknown values
+func (e *{{ .Name }}) UnmarshalJSON(data []byte) error {
+	var value {{ .Type }}
+	if err := jsoniter.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if !{{ .Name }}(value).IsValid() {
+		return fmt.Errorf("unexpected {{ .Name }} value: %v", value)
+	}
+	*e = {{ .Name }}(value)
+	return nil
+}
+
+func (e *{{ .Name }}) UnmarshalJSONIterator(iter *jsoniter.Iterator) {
+	value := {{ .Name }}(iter.Read{{ capitalize .Type }}())
+	if iter.Error != nil && iter.Error != {{ $top.Pkg "io" }}.EOF {
+		return
+	}
+	if !value.IsValid() {
+		iter.ReportError("{{ .Name }}", fmt.Sprintf("unexpected value: %v", value))
+		return
+	}
+	*e = value
+}
+{{- end }}
+{{- end }}
+{{- end }}
+
+{{- /* a type alias */ -}}
+{{- define "alias" }}
+{{- $top := . }}
+{{- with $alias := .Alias }}
 
 // {{ .Name }} ...
 type {{ .Name }} {{ .Type }}
-{{- end -}}
+{{- end }}
+{{- end }}
 
-{{- range .Structs }}
+{{- /* a struct declaration */ -}}
+{{- define "struct" }}
+{{- $top := . }}
+{{- with $struct := .Struct }}
 
 // {{ comment .Name .Description }}
 type {{ .Name }} struct {
 	{{- range .Fields }}
-	// {{ comment .Name .Description }}
-	{{ .Name }} {{ .Type }} {{ $top.Backquote }}json:"{{ .JSONName }}{{ if not .Required }},omitempty{{ end }}"{{ $top.Backquote }}
+	{{- template "field" ($top.ForField $struct .) }}
 {{ end }}
 }
-{{- end -}}
+{{- end }}
+{{- end }}
 
-{{- range $struct := .Structs }}
-{{ if .GenerateCode }}
+{{- /* a field line of a struct declaration */ -}}
+{{- define "field" }}
+{{- $top := . }}
+{{- $struct := .Struct }}
+{{- with .Field }}
+	// {{ comment .Name .Description }}
+	{{- if .ReadOnly }}
+	//
+	// Read only: only sent in responses.
+	{{- end }}
+	{{- if .WriteOnly }}
+	//
+	// Write only: only sent in requests.
+	{{- end }}
+	{{ .Name }} {{ .Type }} {{ $top.Backquote }}json:"{{ .JSONName }}{{ if and (not .Required) (ne .JSONName "-") }},omitempty{{ end }}"{{ range $top.FieldTags . }} {{ . }}{{ end }}{{ $top.Backquote }}
+{{- end }}
+{{- end }}
+
+{{- /* the validation methods of a struct */ -}}
+{{- define "validate" }}
+{{- $top := . }}
+{{- with $struct := .Struct }}
+
+// Validate checks the {{ .Name }} against its schema validation keywords
+func (s *{{ .Name }}) Validate() error {
+	if errs := s.ValidatePath(""); len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+// ValidatePath returns the violations of the {{ .Name }} schema validation
+// keywords, path being the JSON pointer of the value
+func (s *{{ .Name }}) ValidatePath(path string) ValidationErrors {
+	if s == nil {
+		return nil
+	}
+	var errs ValidationErrors
+	{{- range .Fields }}
+	{{- $path := printf "/%s" (jsonPointer .JSONName) }}
+	{{- if eq .JSONName "-" }}{{ $path = "" }}{{ end }}
+	{{- range $top.ValidationChecks . }}
+	if {{ .Cond }} {
+		errs = append(errs, ValidationError{path + {{ printf "%q" $path }}, {{ printf "%q" .Message }}})
+	}
+	{{- end }}
+	{{- if $top.NeedsValidation .Type }}
+	errs = append(errs, validateValue(path+{{ printf "%q" $path }}, s.{{ .Name }})...)
+	{{- end }}
+	{{- end }}
+	{{- range $top.ConditionChecks $struct }}
+	if {{ .Cond }} {
+		errs = append(errs, ValidationError{path + {{ printf "%q" .Path }}, {{ printf "%q" .Message }}})
+	}
+	{{- end }}
+	return errs
+}
+{{- end }}
+{{- end }}
+
+{{- /* the JSON marshalling methods of a struct */ -}}
+{{- define "marshal" }}
+{{- $top := . }}
+{{- with $struct := .Struct }}
 
 // MarshalJSON serializes to JSON
 func (s *{{ .Name }}) MarshalJSON() ([]byte, error) {
@@ -704,6 +1481,10 @@ func (s {{ .Name }}) MarshalJSONStream(stream *jsoniter.Stream) {
 	stream.WriteObjectField("{{ .JSONName }}")
 	{{- if eq .Type "string" }}
 	stream.WriteString(s.{{ .Name }})
+	{{- else if eq .Type "time.Time" }}
+	stream.WriteString(s.{{ .Name }}.Format(time.RFC3339Nano))
+	{{- else if or (eq .Type "*url.URL") (eq .Type "net.IP") (eq .Type "UUID") }}
+	stream.WriteString(s.{{ .Name }}.String())
 	{{- else if isStreamMarshaller .Type }}
 	s.{{ .Name }}.MarshalJSONStream(stream)
 	{{- else }}
@@ -718,6 +1499,13 @@ func (s {{ .Name }}) MarshalJSONStream(stream *jsoniter.Stream) {
 
 	{{- end}}
 	{{- end}}
+	{{- range .PatternProperties }}
+	for key, value := range s.{{ .Name }} {
+		ct.More()
+		stream.WriteObjectField(key)
+		stream.WriteVal(value)
+	}
+	{{- end }}
 	{{- if and .AdditionalType (ne .AdditionalType "false")}}
 	for key, value := range s.AdditionalProperties {
 		ct.More()
@@ -729,6 +1517,14 @@ func (s {{ .Name }}) MarshalJSONStream(stream *jsoniter.Stream) {
 	{{- end}}
 }
 
+{{- end }}
+{{- end }}
+
+{{- /* the JSON unmarshalling methods of a struct */ -}}
+{{- define "unmarshal" }}
+{{- $top := . }}
+{{- with $struct := .Struct }}
+
 func (s *{{ .Name }}) UnmarshalJSON(data []byte) error {
 	iter := jsoniter.ConfigDefault.BorrowIterator(data)
 	s.UnmarshalJSONIterator(iter)
@@ -749,7 +1545,7 @@ func (s *{{ .Name }}) UnmarshalJSONIterator(iter *jsoniter.Iterator) {
 		{{- range .Fields }}
 		{{- if ne .JSONName "-" }}
 		case "{{ .JSONName }}":
-			{{- if and $top.AlwaysAcceptFalse (ne .Type "bool") (ne .Type "OneOfBoolNull")}}
+			{{- if and $top.AlwaysAcceptFalse (ne .Type "bool") (ne .Type "OneOfBoolNull") (ne .Type "Nullable[bool]")}}
 			if iter.WhatIsNext() == jsoniter.BoolValue {
 				if iter.ReadBool() {
 					iter.ReportError("reading field {{ .JSONName }}", "{{ .JSONName }} is 'true', but the expected type is {{ .Type }}")
@@ -773,7 +1569,31 @@ func (s *{{ .Name }}) UnmarshalJSONIterator(iter *jsoniter.Iterator) {
 			{{- end }}
 			{{- else if eq .Type "bool" }}
 			s.{{ .Name }} = iter.ReadBool()
-			{{- else if isIteratorUnmarshaller .Type }}
+			{{- else if eq .Type "time.Time" }}
+			if v, err := time.Parse(time.RFC3339Nano, iter.ReadString()); err != nil {
+				iter.ReportError("reading field {{ .JSONName }}", err.Error())
+			} else {
+				s.{{ .Name }} = v
+			}
+			{{- else if eq .Type "*url.URL" }}
+			if v, err := url.Parse(iter.ReadString()); err != nil {
+				iter.ReportError("reading field {{ .JSONName }}", err.Error())
+			} else {
+				s.{{ .Name }} = v
+			}
+			{{- else if eq .Type "net.IP" }}
+			if v := net.ParseIP(iter.ReadString()); v == nil {
+				iter.ReportError("reading field {{ .JSONName }}", "invalid IP address")
+			} else {
+				s.{{ .Name }} = v
+			}
+			{{- else if eq .Type "UUID" }}
+			if v, err := ParseUUID(iter.ReadString()); err != nil {
+				iter.ReportError("reading field {{ .JSONName }}", err.Error())
+			} else {
+				s.{{ .Name }} = v
+			}
+			{{- else if or (isIteratorUnmarshaller .Type) ($top.IsEnum .Type) }}
 			s.{{ .Name }}.UnmarshalJSONIterator(iter)
 			{{- else }}
 			iter.ReadVal(&s.{{ .Name }})
@@ -787,19 +1607,33 @@ func (s *{{ .Name }}) UnmarshalJSONIterator(iter *jsoniter.Iterator) {
 		{{- end}}
 		{{- end}}
 		default:
+			{{- range .PatternProperties }}
+			if matchPattern({{ printf "%q" .Pattern }}, field) {
+				if s.{{ .Name }} == nil {
+					s.{{ .Name }} = make(map[string]{{ .Type }})
+				}
+				var patternValue {{ .Type }}
+				iter.ReadVal(&patternValue)
+				if iter.Error != nil {
+					return
+				}
+				s.{{ .Name }}[field] = patternValue
+				continue
+			}
+			{{- end }}
 			{{- if eq .AdditionalType "false" }}
 			iter.ReportError("reading {{ .Name }}", "additional property not allowed: \"" + field + "\"")
 			return
 			{{- else if .AdditionalType }}
-            if s.AdditionalProperties == nil {
-                s.AdditionalProperties = make(map[string]{{ .AdditionalType }}, 0)
-            }
-            var additionalValue {{ .AdditionalType }}
+			if s.AdditionalProperties == nil {
+				s.AdditionalProperties = make(map[string]{{ .AdditionalType }}, 0)
+			}
+			var additionalValue {{ .AdditionalType }}
 			iter.ReadVal(&additionalValue)
 			if iter.Error != nil {
 				return
 			}
-            s.AdditionalProperties[field]= additionalValue
+			s.AdditionalProperties[field] = additionalValue
 			{{- else }}
 			// Ignore the additional property
 			iter.Skip()
@@ -817,7 +1651,101 @@ func (s *{{ .Name }}) UnmarshalJSONIterator(iter *jsoniter.Iterator) {
 	{{- end}}
 }
 
-{{- end -}}
-{{- end -}}
-{{- end -}}
+{{- end }}
+{{- end }}
+
+{{- /* the JSON array marshalling methods of a tuple struct */ -}}
+{{- define "tupleMarshal" }}
+{{- $top := . }}
+{{- with $struct := .Struct }}
+
+// MarshalJSON serializes to a JSON array
+func (s *{{ .Name }}) MarshalJSON() ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	stream := jsoniter.ConfigDefault.BorrowStream(buf)
+	s.MarshalJSONStream(stream)
+	stream.Flush()
+	err := stream.Error
+	jsoniter.ConfigDefault.ReturnStream(stream)
+
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (s {{ .Name }}) MarshalJSONStream(stream *jsoniter.Stream) {
+	stream.WriteArrayStart()
+	{{- range $i, $f := .TupleItems }}
+	{{- if $i }}
+	stream.WriteMore()
+	{{- end }}
+	stream.WriteVal(s.{{ .Name }})
+	if stream.Error != nil {
+		return
+	}
+	{{- end }}
+	{{- if and .AdditionalItems (ne .AdditionalItems "false") }}
+	for _, item := range s.AdditionalItems {
+		stream.WriteMore()
+		stream.WriteVal(item)
+	}
+	{{- end }}
+	stream.WriteArrayEnd()
+}
+
+{{- end }}
+{{- end }}
+
+{{- /* the JSON array unmarshalling methods of a tuple struct */ -}}
+{{- define "tupleUnmarshal" }}
+{{- $top := . }}
+{{- with $struct := .Struct }}
+
+func (s *{{ .Name }}) UnmarshalJSON(data []byte) error {
+	iter := jsoniter.ConfigDefault.BorrowIterator(data)
+	s.UnmarshalJSONIterator(iter)
+	err := iter.Error
+	jsoniter.ConfigDefault.ReturnIterator(iter)
+	return err
+}
+
+func (s *{{ .Name }}) UnmarshalJSONIterator(iter *jsoniter.Iterator) {
+	i := 0
+	for ; iter.ReadArray(); i++ {
+		switch i {
+		{{- range $i, $f := .TupleItems }}
+		case {{ $i }}:
+			iter.ReadVal(&s.{{ .Name }})
+		{{- end }}
+		default:
+			{{- if eq .AdditionalItems "false" }}
+			iter.ReportError("reading {{ .Name }}", {{ $top.Pkg "fmt" }}.Sprintf("additional item not allowed at %d", i))
+			return
+			{{- else if .AdditionalItems }}
+			var item {{ .AdditionalItems }}
+			iter.ReadVal(&item)
+			s.AdditionalItems = append(s.AdditionalItems, item)
+			{{- else }}
+			// Ignore the additional item
+			iter.Skip()
+			{{- end }}
+		}
+		if iter.Error != nil {
+			return
+		}
+	}
+	{{- with .TupleMinItems }}
+
+	if i < {{ . }} {
+		iter.ReportError("validating {{ $struct.Name }}", {{ $top.Pkg "fmt" }}.Sprintf("expected at least {{ . }} items, got %d", i))
+	}
+	{{- end }}
+}
+
+{{- end }}
+{{- end }}
+
+{{- /* extra declarations of a struct, e.g. custom methods */ -}}
+{{- define "structExtra" }}{{ end }}
 `))
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 )
 
@@ -12,6 +13,10 @@ type RefResolver struct {
 	schemas []*Schema
 	//           k=uri     v=Schema
 	pathToSchema map[string]*Schema
+	// Loader loads the referenced documents that are not known yet, if not nil
+	Loader Loader
+	// SchemaKeyRequired is used when parsing the loaded documents
+	SchemaKeyRequired bool
 }
 
 // NewRefResolver creates a reference resolver.
@@ -62,11 +67,57 @@ func (r *RefResolver) GetSchemaByReference(schema *Schema) (*Schema, error) {
 	resolvedPath := u.ResolveReference(ref)
 	path, ok := r.pathToSchema[resolvedPath.String()]
 	if !ok {
-		return nil, errors.New("refresolver.GetSchemaByReference: reference not found: " + schema.Reference)
+		path, err = r.loadReference(resolvedPath)
+		if err != nil {
+			return nil, err
+		}
+		if path == nil {
+			return nil, errors.New("refresolver.GetSchemaByReference: reference not found: " + schema.Reference)
+		}
 	}
 	return path, nil
 }
 
+// loadReference loads the document a reference points to, if it was not
+// loaded yet, and returns the referenced schema or nil if it cannot be found.
+func (r *RefResolver) loadReference(ref *url.URL) (*Schema, error) {
+	if r.Loader == nil {
+		return nil, nil
+	}
+	docURI := *ref
+	docURI.Fragment = ""
+	if _, ok := r.pathToSchema[docURI.String()]; ok {
+		// the document is already loaded, the fragment does not exist
+		return nil, nil
+	}
+	b, err := r.Loader.Load(&docURI)
+	if err != nil {
+		return nil, fmt.Errorf("refresolver.GetSchemaByReference: failed to load %s: %v", docURI.String(), err)
+	}
+	schema, err := parseInput(b, docURI.String(), &docURI, r.SchemaKeyRequired)
+	if err != nil {
+		return nil, err
+	}
+	r.schemas = append(r.schemas, schema)
+	if err := r.mapPaths(schema); err != nil {
+		return nil, err
+	}
+	// the subschemas are mapped under the document $id, which may differ
+	// from the document location
+	id, err := url.Parse(schema.ID())
+	if err != nil {
+		return nil, err
+	}
+	id.Fragment = ""
+	if id.String() != docURI.String() {
+		if err := r.InsertURI(docURI.String(), schema); err != nil {
+			return nil, err
+		}
+	}
+	id.Fragment = ref.Fragment
+	return r.pathToSchema[id.String()], nil
+}
+
 func (r *RefResolver) mapPaths(schema *Schema) error {
 	rootURI := &url.URL{}
 	id := schema.ID()
@@ -125,6 +176,14 @@ func (r *RefResolver) updateURIs(schema *Schema, baseURI url.URL, checkCurrentID
 			}
 		}
 	}
+	// a plain name fragment is resolved against the current base URI
+	if schema.Anchor != "" && !ignoreFragments {
+		anchorURI := baseURI
+		anchorURI.Fragment = schema.Anchor
+		if err := r.InsertURI(anchorURI.String(), schema); err != nil {
+			return err
+		}
+	}
 	for k, subSchema := range schema.Definitions {
 		newBaseURI := baseURI
 		newBaseURI.Fragment += "/definitions/" + k
@@ -133,6 +192,22 @@ func (r *RefResolver) updateURIs(schema *Schema, baseURI url.URL, checkCurrentID
 		}
 		r.updateURIs(subSchema, newBaseURI, true, ignoreFragments)
 	}
+	for k, subSchema := range schema.Defs {
+		newBaseURI := baseURI
+		newBaseURI.Fragment += "/$defs/" + k
+		if err := r.InsertURI(newBaseURI.String(), subSchema); err != nil {
+			return err
+		}
+		r.updateURIs(subSchema, newBaseURI, true, ignoreFragments)
+	}
+	for k, subSchema := range schema.ComponentSchemas {
+		newBaseURI := baseURI
+		newBaseURI.Fragment += "/components/schemas/" + k
+		if err := r.InsertURI(newBaseURI.String(), subSchema); err != nil {
+			return err
+		}
+		r.updateURIs(subSchema, newBaseURI, true, ignoreFragments)
+	}
 	for k, subSchema := range schema.Properties {
 		newBaseURI := baseURI
 		newBaseURI.Fragment += "/properties/" + k
@@ -141,6 +216,14 @@ func (r *RefResolver) updateURIs(schema *Schema, baseURI url.URL, checkCurrentID
 		}
 		r.updateURIs(subSchema, newBaseURI, true, ignoreFragments)
 	}
+	for k, subSchema := range schema.PatternProperties {
+		newBaseURI := baseURI
+		newBaseURI.Fragment += "/patternProperties/" + k
+		if err := r.InsertURI(newBaseURI.String(), subSchema); err != nil {
+			return err
+		}
+		r.updateURIs(subSchema, newBaseURI, true, ignoreFragments)
+	}
 	if schema.AdditionalProperties != nil {
 		newBaseURI := baseURI
 		newBaseURI.Fragment += "/additionalProperties"
@@ -151,6 +234,32 @@ func (r *RefResolver) updateURIs(schema *Schema, baseURI url.URL, checkCurrentID
 		newBaseURI.Fragment += "/items"
 		r.updateURIs(schema.Items, newBaseURI, true, ignoreFragments)
 	}
+	prefixItems, additionalItems := schema.tupleKeywords()
+	for i, item := range schema.PrefixItems {
+		newBaseURI := baseURI
+		newBaseURI.Fragment += "/" + prefixItems + "/" + strconv.Itoa(i)
+		if err := r.InsertURI(newBaseURI.String(), item); err != nil {
+			return err
+		}
+		r.updateURIs(item, newBaseURI, true, ignoreFragments)
+	}
+	if schema.AdditionalItems != nil {
+		newBaseURI := baseURI
+		newBaseURI.Fragment += "/" + additionalItems
+		r.updateURIs(schema.AdditionalItems, newBaseURI, true, ignoreFragments)
+	}
+	for keyword, subSchema := range map[string]*Schema{"if": schema.If, "then": schema.Then, "else": schema.Else} {
+		if subSchema != nil {
+			newBaseURI := baseURI
+			newBaseURI.Fragment += "/" + keyword
+			r.updateURIs(subSchema, newBaseURI, true, ignoreFragments)
+		}
+	}
+	for k, subSchema := range schema.DependentSchemas {
+		newBaseURI := baseURI
+		newBaseURI.Fragment += "/" + schema.dependentSchemasKeyword() + "/" + k
+		r.updateURIs(subSchema, newBaseURI, true, ignoreFragments)
+	}
 	return nil
 }
 
@@ -0,0 +1,218 @@
+package generate
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// tagNamings are the naming strategies of the struct tags, converting the
+// JSON name of a field.
+var tagNamings = map[string]func(words []string) string{
+	"snake_case": func(words []string) string {
+		return strings.ToLower(strings.Join(words, "_"))
+	},
+	"kebab-case": func(words []string) string {
+		return strings.ToLower(strings.Join(words, "-"))
+	},
+	"camelCase": func(words []string) string {
+		for i, w := range words {
+			if i == 0 {
+				words[i] = strings.ToLower(w)
+			} else {
+				words[i] = capitaliseFirstLetter(strings.ToLower(w))
+			}
+		}
+		return strings.Join(words, "")
+	},
+	"PascalCase": func(words []string) string {
+		for i, w := range words {
+			words[i] = capitaliseFirstLetter(strings.ToLower(w))
+		}
+		return strings.Join(words, "")
+	},
+}
+
+// tagName returns the name of a field in a struct tag with the naming
+// strategy, the JSON name itself if the strategy is empty or "json".
+func tagName(jsonName string, naming string) string {
+	if naming == "" || naming == "json" {
+		return jsonName
+	}
+	return tagNamings[naming](splitWords(jsonName))
+}
+
+// checkTagNamings returns an error if a struct tag has an unknown naming
+// strategy.
+func checkTagNamings(tags map[string]string) error {
+	for tag, naming := range tags {
+		if _, ok := tagNamings[naming]; !ok && naming != "" && naming != "json" {
+			return fmt.Errorf("unknown naming strategy %q of the %s tag", naming, tag)
+		}
+		if tag == "json" || tag == "validate" {
+			return fmt.Errorf("the %s tag cannot be added", tag)
+		}
+	}
+	return nil
+}
+
+// splitWords splits a name on the non letters or digits, and the lower to
+// upper case changes, e.g. "userID" and "user_id" are "user", "ID".
+func splitWords(s string) []string {
+	var words []string
+	for _, part := range splitOnAll(s, isNotAGoNameCharacter) {
+		runes := []rune(part)
+		start := 0
+		for i := 1; i < len(runes); i++ {
+			lowerToUpper := unicode.IsUpper(runes[i]) && !unicode.IsUpper(runes[i-1])
+			// the last upper case letter of an acronym starts a word, e.g. "HTTPServer"
+			acronymEnd := unicode.IsUpper(runes[i]) && i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1])
+			if lowerToUpper || acronymEnd {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+		if start < len(runes) {
+			words = append(words, string(runes[start:]))
+		}
+	}
+	return words
+}
+
+// FieldTags returns the struct tags of the field besides the json one: the
+// tags of the OutputOptions, the validate tag, and the tags of the x-go-tags
+// extension overriding them.
+func (d *OutputData) FieldTags(f Field) []string {
+	custom, _ := structTagKeys(f.Tags)
+	isCustom := make(map[string]bool, len(custom))
+	for _, key := range custom {
+		isCustom[key] = true
+	}
+
+	names := make([]string, 0, len(d.tags))
+	for name := range d.tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var tags []string
+	for _, name := range names {
+		if isCustom[name] {
+			continue
+		}
+		value := "-"
+		if f.JSONName != "-" {
+			value = tagName(f.JSONName, d.tags[name])
+			if !f.Required {
+				value += ",omitempty"
+			}
+		}
+		tags = append(tags, name+":"+strconv.Quote(value))
+	}
+	if d.validateTags && !isCustom["validate"] {
+		if rules := validateRules(f); len(rules) != 0 {
+			tags = append(tags, "validate:"+strconv.Quote(strings.Join(rules, ",")))
+		}
+	}
+	if f.Tags != "" {
+		tags = append(tags, f.Tags)
+	}
+	return tags
+}
+
+// validateRules returns the go-playground/validator rules of the field
+// validation keywords that have one.
+func validateRules(f Field) []string {
+	var rules []string
+	nillable := f.IsPointer() || strings.HasPrefix(f.Type, "[]") || strings.HasPrefix(f.Type, "map[") || f.Type == "interface{}"
+	if f.Required && nillable {
+		// the validator "required" of the other types rejects their zero value
+		rules = append(rules, "required")
+	}
+	if v := f.Validation; v != nil {
+		length := func(min *int, max *int) {
+			if min != nil {
+				rules = append(rules, fmt.Sprintf("min=%d", *min))
+			}
+			if max != nil {
+				rules = append(rules, fmt.Sprintf("max=%d", *max))
+			}
+		}
+		switch f.Type {
+		case "string":
+			length(v.MinLength, v.MaxLength)
+		case "float32", "float64":
+			if v.Minimum != nil {
+				rules = append(rules, limitRule("gte", "gt", v.ExclusiveMinimum)+"="+v.Minimum.String())
+			}
+			if v.Maximum != nil {
+				rules = append(rules, limitRule("lte", "lt", v.ExclusiveMaximum)+"="+v.Maximum.String())
+			}
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+			// the validator cannot compare the integers to other numbers
+			unsigned := strings.HasPrefix(f.Type, "uint")
+			if v.Minimum != nil && isInteger(*v.Minimum) && !(unsigned && v.Minimum.IsNegative()) {
+				rules = append(rules, limitRule("gte", "gt", v.ExclusiveMinimum)+"="+v.Minimum.String())
+			}
+			if v.Maximum != nil && isInteger(*v.Maximum) && !(unsigned && v.Maximum.IsNegative()) {
+				rules = append(rules, limitRule("lte", "lt", v.ExclusiveMaximum)+"="+v.Maximum.String())
+			}
+		default:
+			if strings.HasPrefix(f.Type, "[]") {
+				length(v.MinItems, v.MaxItems)
+				if v.UniqueItems {
+					rules = append(rules, "unique")
+				}
+			} else if strings.HasPrefix(f.Type, "map[") {
+				length(v.MinProperties, v.MaxProperties)
+			}
+		}
+	}
+	if len(rules) != 0 && !f.Required {
+		rules = append([]string{"omitempty"}, rules...)
+	}
+	return rules
+}
+
+func limitRule(inclusive string, exclusive string, isExclusive bool) string {
+	if isExclusive {
+		return exclusive
+	}
+	return inclusive
+}
+
+// structTagKeys returns the keys of a struct tag, e.g. "gorm" and "xml" for
+// `gorm:"primaryKey" xml:"id"`, or an error if it is not a valid struct tag.
+func structTagKeys(tag string) ([]string, error) {
+	var keys []string
+	for {
+		tag = strings.TrimLeft(tag, " ")
+		if tag == "" {
+			return keys, nil
+		}
+		i := strings.Index(tag, ":\"")
+		if i <= 0 || strings.ContainsAny(tag[:i], " \"") {
+			return nil, fmt.Errorf("invalid struct tag %q", tag)
+		}
+		key := tag[:i]
+		tag = tag[i+1:]
+		// the end of the quoted value
+		j := 1
+		for j < len(tag) && tag[j] != '"' {
+			if tag[j] == '\\' {
+				j++
+			}
+			j++
+		}
+		if j >= len(tag) {
+			return nil, fmt.Errorf("invalid struct tag value of %s", key)
+		}
+		if _, err := strconv.Unquote(tag[:j+1]); err != nil {
+			return nil, fmt.Errorf("invalid struct tag value of %s", key)
+		}
+		keys = append(keys, key)
+		tag = tag[j+1:]
+	}
+}
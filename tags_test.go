@@ -0,0 +1,102 @@
+package generate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestTagNamings(t *testing.T) {
+	tests := []struct {
+		jsonName string
+		naming   string
+		expected string
+	}{
+		{"userID", "", "userID"},
+		{"userID", "json", "userID"},
+		{"userID", "snake_case", "user_id"},
+		{"HTTPServer", "snake_case", "http_server"},
+		{"first-name", "camelCase", "firstName"},
+		{"first_name", "PascalCase", "FirstName"},
+		{"firstName", "kebab-case", "first-name"},
+		{"address2Line", "snake_case", "address2_line"},
+	}
+	for _, test := range tests {
+		if actual := tagName(test.jsonName, test.naming); actual != test.expected {
+			t.Errorf("expected %s of %q to be %q, got %q", test.naming, test.jsonName, test.expected, actual)
+		}
+	}
+	if err := checkTagNamings(map[string]string{"db": "SCREAMING"}); err == nil {
+		t.Error("expected an error for an unknown naming strategy")
+	}
+	if err := checkTagNamings(map[string]string{"json": ""}); err == nil {
+		t.Error("expected an error for the json tag")
+	}
+}
+
+func TestFieldTags(t *testing.T) {
+	d := OutputData{
+		tags:         map[string]string{"yaml": "", "db": "snake_case"},
+		validateTags: true,
+	}
+	five := 5
+	min := decimal.NewFromInt(1)
+
+	tests := []struct {
+		field    Field
+		expected []string
+	}{
+		{
+			Field{JSONName: "firstName", Type: "string", Required: true, Validation: &Validation{MaxLength: &five}},
+			[]string{`db:"first_name"`, `yaml:"firstName"`, `validate:"max=5"`},
+		},
+		{
+			Field{JSONName: "count", Type: "int", Validation: &Validation{Minimum: &min, ExclusiveMinimum: true}},
+			[]string{`db:"count,omitempty"`, `yaml:"count,omitempty"`, `validate:"omitempty,gt=1"`},
+		},
+		{
+			Field{JSONName: "tags", Type: "[]string", Required: true, Validation: &Validation{UniqueItems: true}},
+			[]string{`db:"tags"`, `yaml:"tags"`, `validate:"required,unique"`},
+		},
+		{
+			Field{JSONName: "id", Type: "string", Tags: `db:"_id" gorm:"primaryKey"`},
+			[]string{`yaml:"id,omitempty"`, `db:"_id" gorm:"primaryKey"`},
+		},
+		{
+			Field{Name: "AdditionalProperties", JSONName: "-", Type: "map[string]int"},
+			[]string{`db:"-"`, `yaml:"-"`},
+		},
+	}
+	for _, test := range tests {
+		if actual := d.FieldTags(test.field); !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("expected the tags of %s to be %v, got %v", test.field.JSONName, test.expected, actual)
+		}
+	}
+}
+
+func TestStructTagKeys(t *testing.T) {
+	keys, err := structTagKeys(`gorm:"primaryKey;column:id" xml:"id,attr" note:"a \"quoted\" value"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(keys, []string{"gorm", "xml", "note"}) {
+		t.Errorf("unexpected keys %v", keys)
+	}
+	for _, invalid := range []string{`gorm`, `gorm:primaryKey`, `gorm:"primaryKey`, `a b:"c"`} {
+		if _, err := structTagKeys(invalid); err == nil {
+			t.Errorf("expected an error for %q", invalid)
+		}
+	}
+
+	root := &Schema{
+		Title:      "Item",
+		TypeValue:  "object",
+		Properties: map[string]*Schema{"id": {TypeValue: "string", GoTags: `json:"key"`}},
+	}
+	root.Init()
+	if err := New(root).CreateTypes(); err == nil || !strings.Contains(err.Error(), "json tag") {
+		t.Errorf("expected an error for a json tag in x-go-tags, got %v", err)
+	}
+}
@@ -0,0 +1,26 @@
+package test
+
+import (
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	allof "github.com/orus-io/json-schema-generate/test/allof_gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllOf(t *testing.T) {
+	var e allof.Employee
+	if assert.NoError(t, jsoniter.UnmarshalFromString(
+		`{"name": "John", "age": 42, "company": "ACME", "manager": {"name": "Jane", "company": "ACME"}}`, &e)) {
+		assert.Equal(t, "John", e.Name)
+		assert.Equal(t, 42, e.Age)
+		assert.Equal(t, "ACME", e.Company)
+		assert.Equal(t, "Jane", e.Manager.Name)
+	}
+	// "name" is required by the base schema
+	assert.Error(t, jsoniter.UnmarshalFromString(`{"company": "ACME"}`, &e))
+	// "company" is required by the extension
+	assert.Error(t, jsoniter.UnmarshalFromString(`{"name": "John"}`, &e))
+	// additional properties are forbidden by the base schema
+	assert.Error(t, jsoniter.UnmarshalFromString(`{"name": "John", "company": "ACME", "other": 1}`, &e))
+}
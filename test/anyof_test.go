@@ -0,0 +1,85 @@
+package test
+
+import (
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	anyof "github.com/orus-io/json-schema-generate/test/anyof_gen"
+	anyofmatches "github.com/orus-io/json-schema-generate/test/anyofmatches_gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnyOf(t *testing.T) {
+	var d anyof.EventType
+	assert.True(t, d.IsNotSet())
+	_ = assert.NoError(t, jsoniter.UnmarshalFromString("null", &d)) &&
+		assert.True(t, d.IsNil())
+	_ = assert.NoError(t, jsoniter.UnmarshalFromString(`"16"`, &d)) &&
+		assert.True(t, d.IsString()) &&
+		assert.Equal(t, "16", d.String())
+	_ = assert.NoError(t, jsoniter.UnmarshalFromString("16", &d)) &&
+		assert.True(t, d.IsInt()) &&
+		assert.Equal(t, 16, d.Int())
+	_ = assert.NoError(t, jsoniter.UnmarshalFromString("16.2", &d)) &&
+		assert.True(t, d.IsFloat64()) &&
+		assert.Equal(t, 16.2, d.Float64())
+	_ = assert.NoError(t, jsoniter.UnmarshalFromString(`{"kind": "click", "x": 3}`, &d)) &&
+		assert.True(t, d.IsClick()) &&
+		assert.Equal(t, 3, d.Click().X)
+	_ = assert.NoError(t, jsoniter.UnmarshalFromString(`{"kind": "scroll"}`, &d)) &&
+		assert.True(t, d.IsGeneric()) &&
+		assert.Equal(t, "scroll", d.Generic().Kind)
+	assert.Error(t, jsoniter.UnmarshalFromString("true", &d))
+
+	d.SetString("hello")
+	if s, err := jsoniter.MarshalToString(d); assert.NoError(t, err) {
+		assert.Equal(t, `"hello"`, s)
+	}
+}
+
+func TestAnyOfAllMatches(t *testing.T) {
+	var d anyofmatches.EventType
+	_ = assert.NoError(t, jsoniter.UnmarshalFromString(`{"kind": "click", "x": 3}`, &d)) &&
+		assert.True(t, d.IsClick()) &&
+		assert.Equal(t, []anyofmatches.EventTypeEnum{
+			anyofmatches.EventTypeEnumClick,
+			anyofmatches.EventTypeEnumGeneric,
+		}, d.Matches)
+	_ = assert.NoError(t, jsoniter.UnmarshalFromString("16", &d)) &&
+		assert.True(t, d.IsInt()) &&
+		assert.Equal(t, []anyofmatches.EventTypeEnum{
+			anyofmatches.EventTypeEnumInt,
+			anyofmatches.EventTypeEnumFloat64,
+		}, d.Matches)
+	d.SetNil()
+	assert.Nil(t, d.Matches)
+}
+
+func TestAnyOfSameTypes(t *testing.T) {
+	var l anyof.LabelType
+	_ = assert.NoError(t, jsoniter.UnmarshalFromString(`"hello"`, &l)) &&
+		assert.True(t, l.IsString()) &&
+		assert.Equal(t, "hello", l.String())
+	_ = assert.NoError(t, jsoniter.UnmarshalFromString("16", &l)) &&
+		assert.True(t, l.IsInt()) &&
+		assert.Equal(t, 16, l.Int())
+}
+
+func TestAnyOfCompositeTypes(t *testing.T) {
+	var tags anyof.TagsType
+	_ = assert.NoError(t, jsoniter.UnmarshalFromString(`["a", "b"]`, &tags)) &&
+		assert.True(t, tags.IsArray()) &&
+		assert.Equal(t, []string{"a", "b"}, tags.Array())
+	_ = assert.NoError(t, jsoniter.UnmarshalFromString(`"a"`, &tags)) &&
+		assert.True(t, tags.IsString()) &&
+		assert.Equal(t, "a", tags.String())
+
+	var scores anyof.ScoresType
+	_ = assert.NoError(t, jsoniter.UnmarshalFromString(`{"a": 1}`, &scores)) &&
+		assert.True(t, scores.IsObject()) &&
+		assert.Equal(t, map[string]int{"a": 1}, scores.Object())
+	scores.SetInt(3)
+	if s, err := jsoniter.MarshalToString(scores); assert.NoError(t, err) {
+		assert.Equal(t, "3", s)
+	}
+}
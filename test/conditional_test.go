@@ -0,0 +1,37 @@
+package test
+
+import (
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	conditional "github.com/orus-io/json-schema-generate/test/conditional_gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConditionalSchemas(t *testing.T) {
+	var s conditional.Server
+	data := `{"mode": "tls", "port": 443, "cert": "server.crt", "key": "server.key", "proxy": "squid", "proxyPort": 3128}`
+	if assert.NoError(t, jsoniter.UnmarshalFromString(data, &s)) {
+		assert.Equal(t, "server.crt", s.Cert)
+		assert.Equal(t, "server.key", s.Key)
+		assert.Equal(t, 3128, s.ProxyPort)
+		assert.NoError(t, s.Validate())
+	}
+
+	s = conditional.Server{Mode: "tls", Port: 443, Key: "server.key"}
+	assert.EqualError(t, s.Validate(), `/cert: is required when "mode" is "tls"`)
+	s.Mode = "plain"
+	assert.NoError(t, s.Validate())
+
+	s = conditional.Server{Port: 80, Billing: "monthly", Proxy: "squid"}
+	assert.EqualError(t, s.Validate(),
+		`/address: is required when "billing" is present; /proxyPort: is required when "proxy" is present`)
+
+	s.Address = &conditional.Address{Country: "US"}
+	s.ProxyPort = 3128
+	assert.EqualError(t, s.Validate(), `/address/state: is required when "country" is one of "US", "CA"`)
+	s.Address = &conditional.Address{Country: "FR"}
+	assert.EqualError(t, s.Validate(), `/address/region: is required unless "country" is one of "US", "CA"`)
+	s.Address.Region = "IDF"
+	assert.NoError(t, s.Validate())
+}
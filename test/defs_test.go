@@ -0,0 +1,20 @@
+package test
+
+import (
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	defs "github.com/orus-io/json-schema-generate/test/defs_gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefs(t *testing.T) {
+	var shipment defs.Shipment
+	if assert.NoError(t, jsoniter.UnmarshalFromString(
+		`{"from": {"city": "Paris"}, "to": {"city": "Lyon"}, "items": [{"name": "box", "quantity": 2}]}`, &shipment)) {
+		assert.Equal(t, &defs.Address{City: "Paris"}, shipment.From)
+		assert.Equal(t, &defs.Address{City: "Lyon"}, shipment.To)
+		assert.Equal(t, []*defs.Item{{Name: "box", Quantity: 2}}, shipment.Items)
+	}
+	assert.Error(t, jsoniter.UnmarshalFromString(`{"to": {}}`, &shipment))
+}
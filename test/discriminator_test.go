@@ -0,0 +1,33 @@
+package test
+
+import (
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	discriminator "github.com/orus-io/json-schema-generate/test/discriminator_gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscriminatorConst(t *testing.T) {
+	var m discriminator.MessageType
+	_ = assert.NoError(t, jsoniter.UnmarshalFromString(`{"kind": "text", "body": "hello"}`, &m)) &&
+		assert.True(t, m.IsText()) &&
+		assert.Equal(t, "hello", m.Text().Body)
+	_ = assert.NoError(t, jsoniter.UnmarshalFromString(`{"body": "hello", "kind": "ping"}`, &m)) &&
+		assert.True(t, m.IsPing())
+	_ = assert.NoError(t, jsoniter.UnmarshalFromString(`"hello"`, &m)) &&
+		assert.True(t, m.IsString())
+	assert.Error(t, jsoniter.UnmarshalFromString(`{"kind": "other"}`, &m))
+	assert.Error(t, jsoniter.UnmarshalFromString(`{"body": "hello"}`, &m))
+}
+
+func TestDiscriminatorKeyword(t *testing.T) {
+	var s discriminator.ShapeType
+	_ = assert.NoError(t, jsoniter.UnmarshalFromString(`{"shapeType": "round", "radius": 2}`, &s)) &&
+		assert.True(t, s.IsCircle()) &&
+		assert.Equal(t, 2.0, s.Circle().Radius)
+	_ = assert.NoError(t, jsoniter.UnmarshalFromString(`{"shapeType": "square", "side": 3}`, &s)) &&
+		assert.True(t, s.IsSquare()) &&
+		assert.Equal(t, 3.0, s.Square().Side)
+	assert.Error(t, jsoniter.UnmarshalFromString(`{"shapeType": "circle"}`, &s))
+}
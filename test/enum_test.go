@@ -0,0 +1,40 @@
+package test
+
+import (
+	"encoding/json"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	enum "github.com/orus-io/json-schema-generate/test/enum_gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnum(t *testing.T) {
+	var ticket enum.Ticket
+	if assert.NoError(t, jsoniter.UnmarshalFromString(
+		`{"status": "in-progress", "priority": -1, "weight": 1.5, "flag": true, "tags": ["bug"]}`, &ticket)) {
+		assert.Equal(t, enum.StatusInProgress, ticket.Status)
+		assert.Equal(t, enum.PriorityMinus1, ticket.Priority)
+		assert.Equal(t, enum.Weight1Dot5, ticket.Weight)
+		assert.Equal(t, enum.FlagTrue, ticket.Flag)
+		assert.Equal(t, []enum.TagsItems{enum.TagsItemsBug}, ticket.Tags)
+	}
+
+	assert.Error(t, jsoniter.UnmarshalFromString(`{"status": "unknown"}`, &ticket))
+	assert.Error(t, jsoniter.UnmarshalFromString(`{"status": "open", "priority": 3}`, &ticket))
+	assert.Error(t, jsoniter.UnmarshalFromString(`{"status": "open", "flag": false}`, &ticket))
+	assert.Error(t, jsoniter.UnmarshalFromString(`{"status": "open", "tags": ["other"]}`, &ticket))
+	assert.NoError(t, jsoniter.UnmarshalFromString(`{"status": "open", "flag": null}`, &ticket))
+	assert.Equal(t, enum.FlagNull, ticket.Flag)
+
+	var status enum.Status
+	assert.Error(t, json.Unmarshal([]byte(`"unknown"`), &status))
+	assert.NoError(t, json.Unmarshal([]byte(`"closed"`), &status))
+	assert.Equal(t, enum.StatusClosed, status)
+	assert.Equal(t, []enum.Status{enum.StatusOpen, enum.StatusInProgress, enum.StatusClosed}, status.Values())
+
+	ticket = enum.Ticket{Status: enum.StatusOpen, Flag: enum.FlagYes, Priority: enum.Priority2}
+	if s, err := jsoniter.MarshalToString(&ticket); assert.NoError(t, err) {
+		assert.JSONEq(t, `{"status": "open", "flag": "yes", "priority": 2}`, s)
+	}
+}
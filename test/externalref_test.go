@@ -0,0 +1,22 @@
+package test
+
+import (
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	externalref "github.com/orus-io/json-schema-generate/test/externalref_gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExternalRef(t *testing.T) {
+	var customer externalref.Customer
+	if assert.NoError(t, jsoniter.UnmarshalFromString(
+		`{"name": "bob", "billing": {"street": "main street", "country": {"code": "FR"}}}`, &customer)) {
+		assert.Equal(t, &externalref.Address{
+			Street:  "main street",
+			Country: &externalref.Country{Code: "FR"},
+		}, customer.Billing)
+		assert.Nil(t, customer.Shipping)
+	}
+	assert.Error(t, jsoniter.UnmarshalFromString(`{"shipping": {}}`, &customer))
+}
@@ -0,0 +1,71 @@
+package test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	formats "github.com/orus-io/json-schema-generate/test/formats_gen"
+	formatsasstring "github.com/orus-io/json-schema-generate/test/formatsasstring_gen"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormats(t *testing.T) {
+	j := `{
+		"id": "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+		"created": "2020-01-02T03:04:05.5Z",
+		"link": "https://example.com/path?q=1",
+		"address": "192.168.0.1",
+		"data": "aGVsbG8=",
+		"email": "john@example.com",
+		"price": "12.34",
+		"updated": "2020-01-03T00:00:00Z",
+		"history": ["2020-01-01T00:00:00Z"],
+		"hosts": {"gateway": "192.168.0.254"},
+		"links": ["https://example.com/a", "https://example.com/b"],
+		"mirrors": {"eu": "https://eu.example.com/"},
+		"previous": "https://example.com/old",
+		"origin": ["https://example.org/", "2019-12-31T00:00:00Z"]
+	}`
+	var r formats.Resource
+	if assert.NoError(t, jsoniter.UnmarshalFromString(j, &r)) {
+		assert.Equal(t, "6ba7b810-9dad-11d1-80b4-00c04fd430c8", r.Id.String())
+		assert.True(t, time.Date(2020, 1, 2, 3, 4, 5, 500000000, time.UTC).Equal(r.Created))
+		assert.Equal(t, "example.com", r.Link.Host)
+		assert.True(t, net.IPv4(192, 168, 0, 1).Equal(r.Address))
+		assert.Equal(t, []byte("hello"), r.Data)
+		assert.Equal(t, "john@example.com", r.Email)
+		assert.True(t, decimal.RequireFromString("12.34").Equal(r.Price))
+		assert.True(t, time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC).Equal(r.Updated))
+		if assert.Len(t, r.History, 1) {
+			assert.True(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).Equal(r.History[0]))
+		}
+		assert.True(t, net.IPv4(192, 168, 0, 254).Equal(r.Hosts["gateway"]))
+		assert.Equal(t, []string{"https://example.com/a", "https://example.com/b"}, r.Links)
+		assert.Equal(t, map[string]string{"eu": "https://eu.example.com/"}, r.Mirrors)
+		if previous, ok := r.Previous.Get(); assert.True(t, ok) {
+			assert.Equal(t, "https://example.com/old", previous)
+		}
+		if assert.NotNil(t, r.Origin) {
+			assert.Equal(t, "https://example.org/", r.Origin.Url)
+			assert.True(t, time.Date(2019, 12, 31, 0, 0, 0, 0, time.UTC).Equal(r.Origin.Fetched))
+		}
+
+		if s, err := jsoniter.MarshalToString(&r); assert.NoError(t, err) {
+			assert.JSONEq(t, j, s)
+		}
+	}
+
+	assert.Error(t, jsoniter.UnmarshalFromString(`{"id": "not-a-uuid", "created": "2020-01-02T03:04:05Z"}`, &r))
+	assert.Error(t, jsoniter.UnmarshalFromString(`{"id": "6ba7b810-9dad-11d1-80b4-00c04fd430c8", "created": "yesterday"}`, &r))
+	assert.Error(t, jsoniter.UnmarshalFromString(`{"id": "6ba7b810-9dad-11d1-80b4-00c04fd430c8", "created": "2020-01-02T03:04:05Z", "address": "nowhere"}`, &r))
+
+	var sr formatsasstring.StringResource
+	if assert.NoError(t, jsoniter.UnmarshalFromString(j, &sr)) {
+		assert.Equal(t, "6ba7b810-9dad-11d1-80b4-00c04fd430c8", sr.Id)
+		assert.Equal(t, "2020-01-02T03:04:05.5Z", sr.Created)
+		assert.Equal(t, "example.com", sr.Link.Host)
+	}
+}
@@ -1,10 +1,11 @@
 module github.com/orus-io/json-schema-generate/test
 
-go 1.13
+go 1.18
 
 require (
 	github.com/json-iterator/go v1.1.9
 	github.com/orus-io/json-schema-generate v0.0.0-20191223204113-9e474268e241
+	github.com/shopspring/decimal v0.0.0-20191130220710-360f2bc03045
 	github.com/stretchr/testify v1.3.0
 )
 
@@ -0,0 +1,21 @@
+package test
+
+import (
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	gonames "github.com/orus-io/json-schema-generate/test/gonames_gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoNames(t *testing.T) {
+	var product gonames.Product
+	data := `{"product_id": "p1", "sku": "s1", "html_url": "https://example.com/p1", "vendor": {"api_key": "k"}, "state": "sold_out"}`
+	if assert.NoError(t, jsoniter.UnmarshalFromString(data, &product)) {
+		assert.Equal(t, "p1", product.ProductID)
+		assert.Equal(t, "s1", product.SKU)
+		assert.Equal(t, "https://example.com/p1", product.HTMLURL)
+		assert.Equal(t, &gonames.Supplier{Key: "k"}, product.Supplier)
+		assert.Equal(t, gonames.ProductStateSoldOut, product.State)
+	}
+}
@@ -0,0 +1,94 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	multitype "github.com/orus-io/json-schema-generate/test/multitype_gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiType(t *testing.T) {
+	var r multitype.Rule
+	data := `{"match": ["a", "b"], "value": 3, "target": {"host": "example.com", "port": 80}}`
+	if assert.NoError(t, jsoniter.UnmarshalFromString(data, &r)) {
+		assert.True(t, r.Match.IsArray())
+		assert.Equal(t, []string{"a", "b"}, r.Match.Array())
+		assert.True(t, r.Value.IsInt())
+		assert.Equal(t, 3, r.Value.Int())
+		assert.True(t, r.Target.IsObject())
+		assert.Equal(t, "example.com", r.Target.Object().Host)
+
+		b, err := jsoniter.Marshal(&r)
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, data, string(b))
+		}
+	}
+
+	r = multitype.Rule{}
+	data = `{"match": "a", "value": null, "target": "example.com:80"}`
+	if assert.NoError(t, jsoniter.UnmarshalFromString(data, &r)) {
+		assert.True(t, r.Match.IsString())
+		assert.Equal(t, "a", r.Match.String())
+		assert.True(t, r.Value.IsNil())
+		assert.True(t, r.Target.IsString())
+
+		b, err := jsoniter.Marshal(&r)
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, data, string(b))
+		}
+	}
+
+	for value, check := range map[string]func(multitype.ValueType) bool{
+		`"x"`:  multitype.ValueType.IsString,
+		`1.5`:  multitype.ValueType.IsFloat64,
+		`true`: multitype.ValueType.IsBool,
+	} {
+		var v multitype.ValueType
+		if assert.NoError(t, jsoniter.UnmarshalFromString(value, &v)) {
+			assert.True(t, check(v), value)
+		}
+	}
+
+	r.Match.SetArray([]string{"c"})
+	b, err := jsoniter.Marshal(&r)
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"match": ["c"], "value": null, "target": "example.com:80"}`, string(b))
+	}
+	assert.Error(t, jsoniter.UnmarshalFromString(`{"target": {"port": 80}}`, &r))
+}
+
+func TestMultiTypeNumbers(t *testing.T) {
+	var r multitype.Rule
+	if assert.NoError(t, jsoniter.UnmarshalFromString(`{"count": 3}`, &r)) {
+		assert.True(t, r.Count.IsInt())
+		assert.Equal(t, 3, r.Count.Int())
+	}
+	r = multitype.Rule{}
+	if assert.NoError(t, json.Unmarshal([]byte(`{"count": 4}`), &r)) {
+		assert.True(t, r.Count.IsInt())
+		assert.Equal(t, 4, r.Count.Int())
+	}
+	var c multitype.CountType
+	if assert.NoError(t, jsoniter.UnmarshalFromString(`5`, &c)) {
+		assert.Equal(t, 5, c.Int())
+		// the union is a fmt.Stringer formatting the value of any type
+		assert.Equal(t, "5", fmt.Sprint(c))
+	}
+	c.SetString("five")
+	assert.Equal(t, "five", fmt.Sprint(c))
+
+	// the unset unions are omitted
+	for _, marshal := range []func(interface{}) ([]byte, error){json.Marshal, jsoniter.Marshal} {
+		b, err := marshal(&multitype.Rule{})
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, `{}`, string(b))
+		}
+		b, err = marshal(multitype.CountType{})
+		if assert.NoError(t, err) {
+			assert.Equal(t, `null`, string(b))
+		}
+	}
+}
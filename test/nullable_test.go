@@ -0,0 +1,60 @@
+package test
+
+import (
+	"encoding/json"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	nullable "github.com/orus-io/json-schema-generate/test/nullable_gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNullable(t *testing.T) {
+	var p nullable.Profile
+	data := `{"name": "ann", "age": 30, "nickname": null, "tags": ["a"], "address": {"city": "Lyon", "zip": "69001"}, "level": null, "token": "6ba7b810-9dad-11d1-80b4-00c04fd430c8"}`
+	if assert.NoError(t, jsoniter.UnmarshalFromString(data, &p)) {
+		assert.True(t, p.Age.IsSet())
+		assert.Equal(t, uint(30), p.Age.Value())
+		assert.True(t, p.Nickname.IsNull())
+		assert.Equal(t, []string{"a"}, p.Tags.Value())
+		assert.Equal(t, "Lyon", p.Address.Value().City)
+		assert.Equal(t, map[string]string{"zip": "69001"}, p.Address.Value().AdditionalProperties)
+		assert.True(t, p.Level.IsNull())
+		assert.Equal(t, "6ba7b810-9dad-11d1-80b4-00c04fd430c8", p.Token.Value().String())
+		assert.NoError(t, p.Validate())
+
+		b, err := jsoniter.Marshal(&p)
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, data, string(b))
+		}
+		b, err = json.Marshal(&p)
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, data, string(b))
+		}
+	}
+
+	// the absent values are omitted, the null ones are not
+	p = nullable.Profile{Name: "bob", Nickname: nullable.NewNull[string]()}
+	b, err := jsoniter.Marshal(&p)
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"name": "bob", "nickname": null}`, string(b))
+	}
+
+	p = nullable.Profile{}
+	if assert.NoError(t, json.Unmarshal([]byte(`{"name": "cid", "age": null}`), &p)) {
+		assert.True(t, p.Age.IsNull())
+		assert.True(t, p.Nickname.IsEmpty())
+		assert.False(t, p.Address.IsSet())
+	}
+
+	p = nullable.Profile{Name: "dan", Nickname: nullable.NewNullable("too long nickname")}
+	p.Level.Set("medium")
+	assert.EqualError(t, p.Validate(), `/level: unexpected value: medium; /nickname: length must be at most 8`)
+	p.Level.Set(nullable.LevelHigh)
+	p.Nickname.SetNull()
+	assert.NoError(t, p.Validate())
+	if b, err := json.Marshal(p.Level); assert.NoError(t, err) {
+		assert.Equal(t, `"high"`, string(b))
+	}
+	assert.Error(t, jsoniter.UnmarshalFromString(`{"name": "eve", "address": {}}`, &p))
+}
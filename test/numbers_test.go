@@ -0,0 +1,39 @@
+package test
+
+import (
+	"encoding/json"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	numbers "github.com/orus-io/json-schema-generate/test/numbers_gen"
+	numbersprecise "github.com/orus-io/json-schema-generate/test/numbersprecise_gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNumbers(t *testing.T) {
+	j := `{"id": 9007199254740993, "count": 4294967296, "small": -5, "big": -10000000000, "ratio": 0.5, "value": 1.25}`
+	var m numbers.Measure
+	if assert.NoError(t, jsoniter.UnmarshalFromString(j, &m)) {
+		assert.Equal(t, int64(9007199254740993), m.Id)
+		assert.Equal(t, uint(4294967296), m.Count)
+		assert.Equal(t, int32(-5), m.Small)
+		assert.Equal(t, int64(-10000000000), m.Big)
+		assert.Equal(t, float32(0.5), m.Ratio)
+		assert.Equal(t, 1.25, m.Value)
+	}
+	assert.Error(t, jsoniter.UnmarshalFromString(`{"count": -1}`, &m))
+	assert.Error(t, jsoniter.UnmarshalFromString(`{"small": 3000000000}`, &m))
+
+	var e numbers.EitherType
+	_ = assert.NoError(t, jsoniter.UnmarshalFromString("9007199254740993", &e)) &&
+		assert.True(t, e.IsInt64()) &&
+		assert.Equal(t, int64(9007199254740993), e.Int64())
+	_ = assert.NoError(t, jsoniter.UnmarshalFromString("2.5", &e)) &&
+		assert.True(t, e.IsFloat64()) &&
+		assert.Equal(t, 2.5, e.Float64())
+
+	var p numbersprecise.PreciseMeasure
+	if assert.NoError(t, jsoniter.UnmarshalFromString(`{"value": 12345678901234567890.5}`, &p)) {
+		assert.Equal(t, json.Number("12345678901234567890.5"), p.Value)
+	}
+}
@@ -0,0 +1,25 @@
+package test
+
+import (
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	openapi "github.com/orus-io/json-schema-generate/test/openapi_gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPI(t *testing.T) {
+	var pet openapi.Pet
+	if assert.NoError(t, jsoniter.UnmarshalFromString(
+		`{"name": "felix", "nickname": null, "password": "secret", "kind": {"type": "cat", "lives": 9}}`, &pet)) {
+		assert.Equal(t, "felix", pet.Name)
+		assert.Equal(t, 0, pet.Id)
+		assert.True(t, pet.Nickname.IsNull())
+		assert.Equal(t, &openapi.Cat{Type: "cat", Lives: 9}, pet.Kind.Cat())
+	}
+
+	// the read only id is not required in requests
+	assert.NoError(t, jsoniter.UnmarshalFromString(`{"name": "rex", "nickname": "doggy"}`, &pet))
+	assert.Equal(t, "doggy", pet.Nickname.Value())
+	assert.Error(t, jsoniter.UnmarshalFromString(`{"id": 1}`, &pet))
+}
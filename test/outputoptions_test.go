@@ -0,0 +1,19 @@
+package test
+
+import (
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	outputoptions "github.com/orus-io/json-schema-generate/test/outputoptions_gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutputOptions(t *testing.T) {
+	var link outputoptions.Link
+	if assert.NoError(t, jsoniter.UnmarshalFromString(`{"href": "https://example.com/a", "title": "a"}`, &link)) {
+		assert.Equal(t, "example.com", link.Href.Host)
+		if s, err := jsoniter.MarshalToString(&link); assert.NoError(t, err) {
+			assert.JSONEq(t, `{"href": "https://example.com/a", "title": "a"}`, s)
+		}
+	}
+}
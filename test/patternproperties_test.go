@@ -0,0 +1,31 @@
+package test
+
+import (
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	patternproperties "github.com/orus-io/json-schema-generate/test/patternproperties_gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatternProperties(t *testing.T) {
+	var c patternproperties.Catalog
+	data := `{"id": "c1", "labels": {"en": "Shoes", "fr": "Chaussures"}, "metrics": {"total": 3, "count_views": 10, "rate_clicks": 0.5}}`
+	if assert.NoError(t, jsoniter.UnmarshalFromString(data, &c)) {
+		assert.Equal(t, map[string]string{"en": "Shoes", "fr": "Chaussures"}, c.Labels)
+		assert.Equal(t, 3, c.Metrics.Total)
+		assert.Equal(t, map[string]int{"count_views": 10}, c.Metrics.Counts)
+		assert.Equal(t, map[string]float64{"rate_clicks": 0.5}, c.Metrics.PatternProperties2)
+
+		b, err := jsoniter.Marshal(&c)
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, data, string(b))
+		}
+	}
+
+	var m patternproperties.Metrics
+	err := jsoniter.UnmarshalFromString(`{"total": 1, "views": 2}`, &m)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `additional property not allowed: "views"`)
+	assert.Error(t, jsoniter.UnmarshalFromString(`{"count_views": 1.5}`, &m))
+}
@@ -0,0 +1,20 @@
+package test
+
+import (
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	refmap "github.com/orus-io/json-schema-generate/test/refmap_gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefMap(t *testing.T) {
+	var contact refmap.Contact
+	if assert.NoError(t, jsoniter.UnmarshalFromString(
+		`{"name": "bob", "phone": {"number": "555", "country": {"code": "US"}}}`, &contact)) {
+		assert.Equal(t, &refmap.Phone{
+			Number:  "555",
+			Country: &refmap.Country{Code: "US"},
+		}, contact.Phone)
+	}
+}
@@ -0,0 +1,24 @@
+package test
+
+import (
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	shared "github.com/orus-io/json-schema-generate/test/shared_gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSharedPackage(t *testing.T) {
+	var user shared.User
+	if assert.NoError(t, jsoniter.UnmarshalFromString(
+		`{"name": "bob", "id": "123e4567-e89b-12d3-a456-426614174000"}`, &user)) {
+		assert.Equal(t, "123e4567-e89b-12d3-a456-426614174000", user.Id.String())
+	}
+	assert.Error(t, jsoniter.UnmarshalFromString(`{}`, &user))
+
+	var group shared.Group
+	if assert.NoError(t, jsoniter.UnmarshalFromString(`{"name": "admins", "owner": "bob"}`, &group)) {
+		assert.Equal(t, "bob", group.Owner)
+	}
+	assert.Error(t, jsoniter.UnmarshalFromString(`{}`, &group))
+}
@@ -0,0 +1,25 @@
+package test
+
+import (
+	"os"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	splitfiles "github.com/orus-io/json-schema-generate/test/splitfiles_gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitFiles(t *testing.T) {
+	for _, file := range []string{"helpers.go", "splitfiles.go", "address.go", "country.go"} {
+		_, err := os.Stat("splitfiles_gen/" + file)
+		assert.NoError(t, err)
+	}
+
+	var invoice splitfiles.Invoice
+	if assert.NoError(t, jsoniter.UnmarshalFromString(
+		`{"number": "F-1", "status": "sent", "address": {"street": "main street", "country": {"code": "FR"}}}`, &invoice)) {
+		assert.Equal(t, splitfiles.StatusSent, invoice.Status)
+		assert.Equal(t, "FR", invoice.Address.Country.Code)
+	}
+	assert.Error(t, jsoniter.UnmarshalFromString(`{"number": "F-1", "status": "paid"}`, &invoice))
+}
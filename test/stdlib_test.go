@@ -0,0 +1,51 @@
+package test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	stdlib "github.com/orus-io/json-schema-generate/test/stdlib_gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStdlibJSON(t *testing.T) {
+	code, err := ioutil.ReadFile("stdlib_gen/generated.go")
+	if assert.NoError(t, err) {
+		assert.NotContains(t, string(code), "jsoniter")
+	}
+
+	var order stdlib.Order
+	data := `{"id": "a1", "created": "2024-03-01T10:00:00.5Z", "link": "https://example.com/a1", "status": "open", "quantity": 3, "counts": {"x": 1}}`
+	if assert.NoError(t, json.Unmarshal([]byte(data), &order)) {
+		assert.Equal(t, "a1", order.Id)
+		assert.Equal(t, time.Date(2024, 3, 1, 10, 0, 0, 500000000, time.UTC), order.Created)
+		assert.Equal(t, "example.com", order.Link.Host)
+		assert.Equal(t, stdlib.StatusOpen, order.Status)
+		assert.True(t, order.Quantity.IsInt())
+		assert.Equal(t, 3, order.Quantity.Int())
+		assert.Equal(t, map[string]int{"x": 1}, order.Counts)
+
+		b, err := json.Marshal(order)
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, data, string(b))
+		}
+	}
+
+	order = stdlib.Order{}
+	if assert.NoError(t, json.Unmarshal([]byte(`{"id": "a2", "quantity": "three"}`), &order)) {
+		assert.True(t, order.Quantity.IsString())
+		b, err := json.Marshal(&order)
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, `{"id": "a2", "quantity": "three"}`, string(b))
+		}
+	}
+
+	err = json.Unmarshal([]byte(`{"quantity": 1}`), &order)
+	assert.EqualError(t, err, `validating Order: "id" is required but was not present`)
+	err = json.Unmarshal([]byte(`{"id": "a3", "price": 1}`), &order)
+	assert.EqualError(t, err, `reading Order: additional property not allowed: "price"`)
+	assert.Error(t, json.Unmarshal([]byte(`{"id": "a4", "status": "lost"}`), &order))
+	assert.Error(t, json.Unmarshal([]byte(`{"id": 4}`), &order))
+}
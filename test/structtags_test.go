@@ -0,0 +1,26 @@
+package test
+
+import (
+	"reflect"
+	"testing"
+
+	structtags "github.com/orus-io/json-schema-generate/test/structtags_gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStructTags(t *testing.T) {
+	typ := reflect.TypeOf(structtags.Account{})
+	tags := func(name string) reflect.StructTag {
+		field, ok := typ.FieldByName(name)
+		assert.True(t, ok, name)
+		return field.Tag
+	}
+
+	assert.Equal(t, `json:"accountID" db:"account_id" yaml:"accountID" bson:"_id" gorm:"primaryKey"`, string(tags("AccountID")))
+	assert.Equal(t, "display_name", tags("DisplayName").Get("db"))
+	assert.Equal(t, "displayName", tags("DisplayName").Get("bson"))
+	assert.Equal(t, "min=1,max=64", tags("DisplayName").Get("validate"))
+	assert.Equal(t, "age,omitempty", tags("Age").Get("yaml"))
+	assert.Equal(t, "omitempty,gte=0", tags("Age").Get("validate"))
+	assert.Equal(t, "required,unique", tags("Roles").Get("validate"))
+}
@@ -0,0 +1,23 @@
+package test
+
+import (
+	"reflect"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	templatedir "github.com/orus-io/json-schema-generate/test/templatedir_gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateDir(t *testing.T) {
+	var book templatedir.Book
+	if assert.NoError(t, jsoniter.UnmarshalFromString(`{"title": "Dune", "pages": 412}`, &book)) {
+		assert.Equal(t, "Dune", book.Title)
+		assert.Equal(t, 412, book.Pages)
+	}
+	assert.Error(t, jsoniter.UnmarshalFromString(`{}`, &book))
+
+	field, _ := reflect.TypeOf(book).FieldByName("Pages")
+	assert.Equal(t, "pages", field.Tag.Get("yaml"))
+	assert.Equal(t, "Book", book.TypeName())
+}
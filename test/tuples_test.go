@@ -0,0 +1,41 @@
+package test
+
+import (
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	tuples "github.com/orus-io/json-schema-generate/test/tuples_gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTuples(t *testing.T) {
+	var r tuples.Route
+	data := `{"start": [2.35, 48.85], "row": ["a", 1, 0.5, 1.5], "tags": ["x", 1, true]}`
+	if assert.NoError(t, jsoniter.UnmarshalFromString(data, &r)) {
+		assert.Equal(t, 2.35, r.Start.Longitude)
+		assert.Equal(t, 48.85, r.Start.Latitude)
+		assert.Equal(t, "a", r.Row.Name)
+		assert.Equal(t, uint(1), r.Row.Item1)
+		assert.Equal(t, []float64{0.5, 1.5}, r.Row.AdditionalItems)
+		assert.Equal(t, "x", r.Tags.Item0)
+		assert.Equal(t, []interface{}{float64(1), true}, r.Tags.AdditionalItems)
+		assert.NoError(t, r.Validate())
+
+		b, err := jsoniter.Marshal(&r)
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, data, string(b))
+		}
+	}
+
+	var p tuples.Point
+	err := jsoniter.UnmarshalFromString(`[1, 2, 3]`, &p)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "additional item not allowed at 2")
+	}
+	err = jsoniter.UnmarshalFromString(`[1]`, &p)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "expected at least 2 items, got 1")
+	}
+	p = tuples.Point{Longitude: 200}
+	assert.EqualError(t, p.Validate(), "/0: must be less than or equal to 180")
+}
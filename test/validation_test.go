@@ -0,0 +1,59 @@
+package test
+
+import (
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	validation "github.com/orus-io/json-schema-generate/test/validation_gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidation(t *testing.T) {
+	var order validation.Order
+	if assert.NoError(t, jsoniter.UnmarshalFromString(`{
+		"id": "ABC-12",
+		"quantity": 10,
+		"discount": 0.5,
+		"step": 15,
+		"note": "été",
+		"tags": ["a", "b"],
+		"lines": [{"sku": "x", "price": 0}],
+		"customer": {"name": "bob", "attributes": {"a": "1"}}
+	}`, &order)) {
+		assert.NoError(t, order.Validate())
+	}
+
+	if assert.NoError(t, jsoniter.UnmarshalFromString(`{
+		"id": "abc",
+		"quantity": 101,
+		"discount": 1,
+		"step": 12,
+		"note": "é",
+		"tags": ["a", "b", "a", "c"],
+		"lines": [{"sku": "x"}, {"sku": "", "price": -1}],
+		"customer": {"name": "", "attributes": {"a": "1", "b/c": "2", "d": "3"}}
+	}`, &order)) {
+		err := order.Validate()
+		if assert.IsType(t, validation.ValidationErrors{}, err) {
+			var paths []string
+			for _, e := range err.(validation.ValidationErrors) {
+				paths = append(paths, e.Path)
+			}
+			assert.Equal(t, []string{
+				"/customer/attributes",
+				"/customer/name",
+				"/discount",
+				"/id",
+				"/lines/1/price",
+				"/note",
+				"/quantity",
+				"/step",
+				"/tags",
+				"/tags",
+			}, paths)
+		}
+	}
+
+	order = validation.Order{Id: "ABC-1"}
+	assert.EqualError(t, order.Validate(), "/customer: is required")
+}
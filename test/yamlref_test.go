@@ -0,0 +1,22 @@
+package test
+
+import (
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	yamlref "github.com/orus-io/json-schema-generate/test/yamlref_gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestYAMLRef(t *testing.T) {
+	var shelter yamlref.Shelter
+	if assert.NoError(t, jsoniter.UnmarshalFromString(
+		`{"pets": [{"name": "rex", "age": 3, "owner": {"name": "bob"}}]}`, &shelter)) {
+		assert.Equal(t, []*yamlref.Pet{{
+			Name:  "rex",
+			Age:   3,
+			Owner: &yamlref.Owner{Name: "bob"},
+		}}, shelter.Pets)
+	}
+	assert.Error(t, jsoniter.UnmarshalFromString(`{"pets": [{"age": 3}]}`, &shelter))
+}
@@ -0,0 +1,432 @@
+package generate
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Validation holds the validation keywords of a field schema.
+type Validation struct {
+	MinLength *int
+	MaxLength *int
+	Pattern   string
+
+	Minimum          *decimal.Decimal
+	ExclusiveMinimum bool
+	Maximum          *decimal.Decimal
+	ExclusiveMaximum bool
+	MultipleOf       *decimal.Decimal
+
+	MinItems    *int
+	MaxItems    *int
+	UniqueItems bool
+
+	MinProperties *int
+	MaxProperties *int
+}
+
+// ValidationCheck is a check emitted in a generated Validate method
+type ValidationCheck struct {
+	// Cond is a golang expression that is true if the field is invalid
+	Cond string
+	// Message describes the violation
+	Message string
+}
+
+var (
+	minInt64  = decimal.New(math.MinInt64, 0)
+	maxInt64  = decimal.New(math.MaxInt64, 0)
+	maxUint64 = decimal.RequireFromString("18446744073709551615")
+)
+
+// newValidation returns the validation keywords of a schema, or nil if it has none
+func newValidation(schema *Schema) *Validation {
+	v := Validation{
+		MinLength:     schema.MinLength,
+		MaxLength:     schema.MaxLength,
+		MinItems:      schema.MinItems,
+		MaxItems:      schema.MaxItems,
+		UniqueItems:   schema.UniqueItems,
+		MinProperties: schema.MinProperties,
+		MaxProperties: schema.MaxProperties,
+	}
+	// patterns that are not supported by the regexp package are not checked
+	if _, err := regexp.Compile(schema.Pattern); err == nil {
+		v.Pattern = schema.Pattern
+	}
+	v.Minimum, v.ExclusiveMinimum = mergeLimits(schema.Minimum, schema.ExclusiveMinimum, decimal.Decimal.GreaterThan)
+	v.Maximum, v.ExclusiveMaximum = mergeLimits(schema.Maximum, schema.ExclusiveMaximum, decimal.Decimal.LessThan)
+	if !schema.MultipleOf.IsZero() {
+		m := schema.MultipleOf
+		v.MultipleOf = &m
+	}
+	if v == (Validation{}) {
+		return nil
+	}
+	return &v
+}
+
+// mergeLimits merges an inclusive limit and an exclusive one into the most
+// restrictive limit
+func mergeLimits(inclusive *decimal.Decimal, exclusive *ExclusiveLimit, stricter func(a, b decimal.Decimal) bool) (limit *decimal.Decimal, isExclusive bool) {
+	if exclusive == nil {
+		return inclusive, false
+	}
+	if exclusive.Limit == nil {
+		return inclusive, inclusive != nil && exclusive.Exclusive
+	}
+	if inclusive != nil && stricter(*inclusive, *exclusive.Limit) {
+		return inclusive, false
+	}
+	return exclusive.Limit, true
+}
+
+func isInteger(d decimal.Decimal) bool {
+	return d.Equal(d.Truncate(0))
+}
+
+var decimalComparisons = map[string]string{
+	"<":  "LessThan",
+	"<=": "LessThanOrEqual",
+	">":  "GreaterThan",
+	">=": "GreaterThanOrEqual",
+}
+
+// numericCheck returns an expression comparing value to limit with op, or an
+// empty string if the type cannot be compared.
+func (d *OutputData) numericCheck(typ string, value string, op string, limit decimal.Decimal) string {
+	switch typ {
+	case "int", "int8", "int16", "int32", "int64":
+		if !isInteger(limit) {
+			return fmt.Sprintf("float64(%s) %s %s", value, op, limit)
+		}
+		if limit.LessThan(minInt64) || limit.GreaterThan(maxInt64) {
+			return ""
+		}
+		return fmt.Sprintf("int64(%s) %s %s", value, op, limit)
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		if !isInteger(limit) {
+			return fmt.Sprintf("float64(%s) %s %s", value, op, limit)
+		}
+		if limit.IsNegative() || limit.GreaterThan(maxUint64) {
+			return ""
+		}
+		return fmt.Sprintf("uint64(%s) %s %s", value, op, limit)
+	case "float32", "float64":
+		return fmt.Sprintf("float64(%s) %s %s", value, op, limit)
+	case "decimal.Decimal":
+		return fmt.Sprintf("%s.%s(%s.RequireFromString(%q))",
+			value, decimalComparisons[op], d.Pkg("github.com/shopspring/decimal"), limit)
+	}
+	return ""
+}
+
+// multipleOfCheck returns an expression that is true if value is not a multiple
+// of m, or an empty string if the type cannot be checked.
+func (d *OutputData) multipleOfCheck(typ string, value string, m decimal.Decimal) string {
+	switch typ {
+	case "int", "int8", "int16", "int32", "int64":
+		if isInteger(m) && !m.GreaterThan(maxInt64) {
+			return fmt.Sprintf("int64(%s)%%%s != 0", value, m)
+		}
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		if isInteger(m) && !m.GreaterThan(maxUint64) {
+			return fmt.Sprintf("uint64(%s)%%%s != 0", value, m)
+		}
+	case "float32", "float64":
+	case "decimal.Decimal":
+		return fmt.Sprintf("!%s.Mod(%s.RequireFromString(%q)).IsZero()",
+			value, d.Pkg("github.com/shopspring/decimal"), m)
+	default:
+		return ""
+	}
+	return fmt.Sprintf("!%s.NewFromFloat(float64(%s)).Mod(%s.RequireFromString(%q)).IsZero()",
+		d.Pkg("github.com/shopspring/decimal"), value, d.Pkg("github.com/shopspring/decimal"), m)
+}
+
+// ValidationChecks returns the checks of the field validation keywords
+func (d *OutputData) ValidationChecks(f Field) []ValidationCheck {
+	var checks []ValidationCheck
+	add := func(cond string, format string, a ...interface{}) {
+		if cond != "" {
+			checks = append(checks, ValidationCheck{Cond: cond, Message: fmt.Sprintf(format, a...)})
+		}
+	}
+
+	if f.Required && f.IsPointer() {
+		add("s."+f.Name+" == nil", "is required")
+	}
+
+	v := f.Validation
+	if v == nil {
+		return checks
+	}
+
+	value := "s." + f.Name
+	typ := f.Type
+	// the checks only apply to the values that are present
+	guard := ""
+	for base, emptyType := range d.EmptyTypes {
+		if emptyType == typ {
+			guard = value + ".Valid && "
+			value += "." + capitaliseFirstLetter(base)
+			typ = base
+		}
+	}
+	if valueType, ok := isNullableType(typ); ok {
+		guard = value + ".IsSet() && "
+		value += ".Value()"
+		typ = valueType
+	}
+	if guard == "" && !f.Required {
+		guard = "!IsEmpty(" + value + ") && "
+	}
+	check := func(cond string, format string, a ...interface{}) {
+		if cond != "" {
+			add(guard+cond, format, a...)
+		}
+	}
+
+	switch {
+	case typ == "string":
+		if v.MinLength != nil {
+			check(fmt.Sprintf("%s.RuneCountInString(%s) < %d", d.Pkg("unicode/utf8"), value, *v.MinLength),
+				"length must be at least %d", *v.MinLength)
+		}
+		if v.MaxLength != nil {
+			check(fmt.Sprintf("%s.RuneCountInString(%s) > %d", d.Pkg("unicode/utf8"), value, *v.MaxLength),
+				"length must be at most %d", *v.MaxLength)
+		}
+		if v.Pattern != "" {
+			check(fmt.Sprintf("!matchPattern(%q, %s)", v.Pattern, value),
+				"must match the pattern %q", v.Pattern)
+		}
+	case strings.HasPrefix(typ, "[]") && typ != "[]byte":
+		if v.MinItems != nil {
+			check(fmt.Sprintf("len(%s) < %d", value, *v.MinItems),
+				"must have at least %d items", *v.MinItems)
+		}
+		if v.MaxItems != nil {
+			check(fmt.Sprintf("len(%s) > %d", value, *v.MaxItems),
+				"must have at most %d items", *v.MaxItems)
+		}
+		if v.UniqueItems {
+			check(fmt.Sprintf("!hasUniqueItems(%s)", value), "must have unique items")
+		}
+	case strings.HasPrefix(typ, "map["):
+		if v.MinProperties != nil {
+			check(fmt.Sprintf("len(%s) < %d", value, *v.MinProperties),
+				"must have at least %d properties", *v.MinProperties)
+		}
+		if v.MaxProperties != nil {
+			check(fmt.Sprintf("len(%s) > %d", value, *v.MaxProperties),
+				"must have at most %d properties", *v.MaxProperties)
+		}
+	default:
+		if v.Minimum != nil {
+			if v.ExclusiveMinimum {
+				check(d.numericCheck(typ, value, "<=", *v.Minimum), "must be greater than %s", v.Minimum)
+			} else {
+				check(d.numericCheck(typ, value, "<", *v.Minimum), "must be greater than or equal to %s", v.Minimum)
+			}
+		}
+		if v.Maximum != nil {
+			if v.ExclusiveMaximum {
+				check(d.numericCheck(typ, value, ">=", *v.Maximum), "must be less than %s", v.Maximum)
+			} else {
+				check(d.numericCheck(typ, value, ">", *v.Maximum), "must be less than or equal to %s", v.Maximum)
+			}
+		}
+		if v.MultipleOf != nil {
+			check(d.multipleOfCheck(typ, value, *v.MultipleOf), "must be a multiple of %s", v.MultipleOf)
+		}
+	}
+	return checks
+}
+
+// plainTypes are the golang types that have no nested validation
+var plainTypes = []string{
+	"bool", "string", "byte", "interface{}",
+	"int", "int8", "int16", "int32", "int64",
+	"uint", "uint8", "uint16", "uint32", "uint64",
+	"float32", "float64",
+	"time.Time", "url.URL", "net.IP", "UUID", "json.Number", "decimal.Decimal",
+	"OneOfStringNull", "OneOfNumberNull", "OneOfBoolNull",
+}
+
+// NeedsValidation returns true if values of the given type may hold generated
+// types that have a validation method
+func (d *OutputData) NeedsValidation(typ string) bool {
+	for {
+		switch {
+		case strings.HasPrefix(typ, "[]"):
+			typ = typ[2:]
+		case strings.HasPrefix(typ, "*"):
+			typ = typ[1:]
+		case strings.HasPrefix(typ, "map[string]"):
+			typ = typ[len("map[string]"):]
+		case strings.HasPrefix(typ, "Nullable["):
+			typ, _ = isNullableType(typ)
+		default:
+			if contains(plainTypes, typ) {
+				return false
+			}
+			for _, emptyType := range d.EmptyTypes {
+				if emptyType == typ {
+					return false
+				}
+			}
+			return true
+		}
+	}
+}
+
+// jsonPointerEscape escapes a JSON pointer reference token
+func jsonPointerEscape(s string) string {
+	return strings.Replace(strings.Replace(s, "~", "~0", -1), "/", "~1", -1)
+}
+
+// ConditionCheck is a conditional requirement emitted in a generated Validate
+// method
+type ConditionCheck struct {
+	// Path is the JSON pointer of the required property in the object
+	Path string
+	// Cond is a golang expression that is true if the property is missing
+	Cond string
+	// Message describes the violation
+	Message string
+}
+
+// ConditionChecks returns the checks of the conditional requirements of the
+// struct. The conditions on unknown properties, or on values that cannot be
+// compared to their fields, are skipped.
+func (d *OutputData) ConditionChecks(s Struct) []ConditionCheck {
+	fields := make(map[string]Field, len(s.Fields))
+	for _, f := range s.Fields {
+		if f.JSONName != "-" {
+			fields[f.JSONName] = f
+		}
+	}
+
+	var checks []ConditionCheck
+conditions:
+	for _, c := range s.Conditions {
+		var conds, descriptions []string
+		for _, name := range c.Present {
+			f, ok := fields[name]
+			if !ok {
+				continue conditions
+			}
+			conds = append(conds, "!IsEmpty(s."+f.Name+")")
+			if _, ok := c.Values[name]; !ok {
+				descriptions = append(descriptions, strconv.Quote(name)+" is present")
+			}
+		}
+		names := make([]string, 0, len(c.Values))
+		for name := range c.Values {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			f, ok := fields[name]
+			if !ok {
+				continue conditions
+			}
+			cond, ok := d.valueCheck(f, c.Values[name])
+			if !ok {
+				continue conditions
+			}
+			description := strconv.Quote(name) + " is "
+			if !contains(c.Present, name) {
+				// an absent property matches the condition
+				cond = "(IsEmpty(s." + f.Name + ") || " + cond + ")"
+				description += "absent or "
+			}
+			conds = append(conds, cond)
+			if len(c.Values[name]) == 1 {
+				descriptions = append(descriptions, description+c.Values[name][0])
+			} else {
+				descriptions = append(descriptions, description+"one of "+strings.Join(c.Values[name], ", "))
+			}
+		}
+		cond := strings.Join(conds, " && ")
+		message := "is required when " + strings.Join(descriptions, " and ")
+		if c.Unless {
+			cond = "!(" + cond + ")"
+			message = "is required unless " + strings.Join(descriptions, " and ")
+		}
+		for _, name := range c.Required {
+			f, ok := fields[name]
+			if !ok || f.Required {
+				continue
+			}
+			checks = append(checks, ConditionCheck{
+				Path:    "/" + jsonPointerEscape(name),
+				Cond:    cond + " && IsEmpty(s." + f.Name + ")",
+				Message: message,
+			})
+		}
+	}
+	return checks
+}
+
+// valueCheck returns a golang expression that is true if the field has one of
+// the JSON values, false if they cannot be compared to the field
+func (d *OutputData) valueCheck(f Field, values []string) (string, bool) {
+	value, typ := "s."+f.Name, f.Type
+	guard := ""
+	if valueType, ok := isNullableType(typ); ok {
+		guard = value + ".IsSet() && "
+		value += ".Value()"
+		typ = valueType
+	}
+	for _, e := range d.Enums {
+		if e.Name == typ && !e.Mixed {
+			typ = e.Type
+		}
+	}
+
+	var conds []string
+	for _, raw := range values {
+		var v interface{}
+		if err := json.Unmarshal([]byte(raw), &v); err != nil {
+			return "", false
+		}
+		switch v := v.(type) {
+		case string:
+			if typ != "string" {
+				return "", false
+			}
+			conds = append(conds, guard+value+" == "+strconv.Quote(v))
+		case float64:
+			isInt := strings.HasPrefix(typ, "int") || strings.HasPrefix(typ, "uint")
+			if !(isInt && v == math.Trunc(v)) && typ != "float32" && typ != "float64" {
+				return "", false
+			}
+			conds = append(conds, guard+value+" == "+strings.TrimSpace(raw))
+		case bool:
+			if typ != "bool" {
+				return "", false
+			}
+			conds = append(conds, guard+value+" == "+strconv.FormatBool(v))
+		case nil:
+			if guard == "" {
+				return "", false
+			}
+			conds = append(conds, "s."+f.Name+".IsNull()")
+		default:
+			return "", false
+		}
+	}
+	if len(conds) == 1 {
+		return conds[0], true
+	}
+	return "(" + strings.Join(conds, " || ") + ")", true
+}
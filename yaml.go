@@ -0,0 +1,137 @@
+package generate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlPosition is the position in the YAML document of a value written at
+// offset in the converted JSON document.
+type yamlPosition struct {
+	offset int
+	line   int
+	column int
+}
+
+// isYAML returns true if the document is YAML, either by the file extension
+// or, failing that, because it is not a JSON object.
+func isYAML(file string, b []byte) bool {
+	switch strings.ToLower(path.Ext(file)) {
+	case ".yaml", ".yml":
+		return true
+	case ".json":
+		return false
+	}
+	b = bytes.TrimSpace(b)
+	return len(b) != 0 && b[0] != '{'
+}
+
+// yamlToJSON converts a YAML document to JSON, and returns the YAML position
+// of the JSON values.
+func yamlToJSON(b []byte) ([]byte, []yamlPosition, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, nil, err
+	}
+	c := yamlConverter{}
+	if err := c.convert(&doc); err != nil {
+		return nil, nil, err
+	}
+	return c.buf.Bytes(), c.positions, nil
+}
+
+type yamlConverter struct {
+	buf       bytes.Buffer
+	positions []yamlPosition
+}
+
+func (c *yamlConverter) convert(node *yaml.Node) error {
+	if node.Kind != yaml.DocumentNode && node.Kind != yaml.AliasNode {
+		c.positions = append(c.positions, yamlPosition{offset: c.buf.Len(), line: node.Line, column: node.Column})
+	}
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return errors.New("empty YAML document")
+		}
+		return c.convert(node.Content[0])
+	case yaml.AliasNode:
+		return c.convert(node.Alias)
+	case yaml.MappingNode:
+		c.buf.WriteByte('{')
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Kind != yaml.ScalarNode {
+				return fmt.Errorf("line %d, column %d: unsupported non scalar mapping key", key.Line, key.Column)
+			}
+			if i != 0 {
+				c.buf.WriteByte(',')
+			}
+			k, _ := json.Marshal(key.Value)
+			c.buf.Write(k)
+			c.buf.WriteByte(':')
+			if err := c.convert(value); err != nil {
+				return err
+			}
+		}
+		c.buf.WriteByte('}')
+	case yaml.SequenceNode:
+		c.buf.WriteByte('[')
+		for i, item := range node.Content {
+			if i != 0 {
+				c.buf.WriteByte(',')
+			}
+			if err := c.convert(item); err != nil {
+				return err
+			}
+		}
+		c.buf.WriteByte(']')
+	case yaml.ScalarNode:
+		return c.convertScalar(node)
+	}
+	return nil
+}
+
+func (c *yamlConverter) convertScalar(node *yaml.Node) error {
+	switch node.ShortTag() {
+	case "!!int", "!!float":
+		// keep the literal numbers as is, so that no precision is lost
+		if json.Valid([]byte(node.Value)) {
+			c.buf.WriteString(node.Value)
+			return nil
+		}
+	case "!!bool", "!!null":
+	default:
+		// strings, timestamps, binaries...
+		b, _ := json.Marshal(node.Value)
+		c.buf.Write(b)
+		return nil
+	}
+	var v interface{}
+	if err := node.Decode(&v); err != nil {
+		return fmt.Errorf("line %d, column %d: %v", node.Line, node.Column, err)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("line %d, column %d: %v", node.Line, node.Column, err)
+	}
+	c.buf.Write(b)
+	return nil
+}
+
+// yamlLineAndColumn returns the YAML position of the value at offset in the
+// converted JSON document.
+func yamlLineAndColumn(positions []yamlPosition, offset int) (line int, column int, err error) {
+	i := sort.Search(len(positions), func(i int) bool { return positions[i].offset > offset })
+	if i == 0 {
+		return 0, 0, fmt.Errorf("couldn't find offset %d in the YAML document", offset)
+	}
+	return positions[i-1].line, positions[i-1].column, nil
+}
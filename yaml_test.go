@@ -0,0 +1,57 @@
+package generate
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestThatYAMLSchemasCanBeParsed(t *testing.T) {
+	s := `
+$schema: http://json-schema.org/schema#
+title: root
+properties:
+  count:
+    type: integer
+    multipleOf: 0.01
+  tags:
+    type: [array, "null"]
+    items: &tag
+      type: string
+  other:
+    items: *tag
+`
+	so, err := parseInput([]byte(s), "root.yaml", &url.URL{Scheme: "file", Path: "/root.yaml"}, true)
+	if err != nil {
+		t.Fatal("It should be possible to parse a YAML schema, but received error:", err)
+	}
+	if so.Title != "root" {
+		t.Errorf("expected the title to be root, got %s", so.Title)
+	}
+	if so.Properties["count"].MultipleOf.String() != "0.01" {
+		t.Errorf("expected multipleOf to be 0.01, got %s", so.Properties["count"].MultipleOf)
+	}
+	if types, _ := so.Properties["tags"].MultiType(); len(types) != 2 {
+		t.Errorf("expected 2 types, got %v", types)
+	}
+	if typ, _ := so.Properties["other"].Items.Type(); typ != "string" {
+		t.Errorf("expected the aliased items to be strings, got %s", typ)
+	}
+}
+
+func TestThatYAMLErrorsHaveAPosition(t *testing.T) {
+	s := `$schema: http://json-schema.org/schema#
+properties:
+  name:
+    required: yes
+`
+	_, err := parseInput([]byte(s), "root.yml", &url.URL{Scheme: "file", Path: "/root.yml"}, true)
+	if err == nil || !strings.Contains(err.Error(), "line 4, character 15") {
+		t.Errorf("expected an error at line 4, character 15, got %v", err)
+	}
+
+	_, err = parseInput([]byte("title: [a"), "root.yml", &url.URL{Scheme: "file", Path: "/root.yml"}, true)
+	if err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("expected a syntax error at line 1, got %v", err)
+	}
+}